@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -27,6 +28,21 @@ func (h HttpServerConfig) URL() string {
 	return fmt.Sprintf("%s://%s:%s", h.Protocol, h.Host, h.Port)
 }
 
+type GrpcServerConfig struct {
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+}
+
+func (g GrpcServerConfig) Addr() string {
+	return fmt.Sprintf("%s:%s", g.Host, g.Port)
+}
+
+// Transports the storefront can talk to the other services over.
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
 type DBConfig struct {
 	Host     string `yaml:"host"`
 	Port     string `yaml:"port"`
@@ -42,31 +58,171 @@ type KafkaConfig struct {
 	GroupID       string   `yaml:"groupID"`
 }
 
+type SagaConfig struct {
+	// ReplyTimeout bounds how long POST /orders waits for the inventory reply.
+	ReplyTimeout time.Duration `yaml:"replyTimeout"`
+}
+
+type ExpiryConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	// PendingTimeout and PaymentTimeout are measured from the last status change.
+	PendingTimeout time.Duration `yaml:"pendingTimeout"`
+	PaymentTimeout time.Duration `yaml:"paymentTimeout"`
+	// ReplyTimeout is how long an order waits in Compensating, Cancelling or
+	// Refunding for a reply before the command is published again.
+	ReplyTimeout time.Duration `yaml:"replyTimeout"`
+	BatchSize    int           `yaml:"batchSize"`
+}
+
+type IdempotencyConfig struct {
+	// Retention is how long an Idempotency-Key and its response are kept.
+	Retention time.Duration `yaml:"retention"`
+}
+
+// Outbox modes select what publishes the outbox of a service to Kafka.
+const (
+	// OutboxModeDebezium leaves the outbox to the Debezium connector in Kafka Connect.
+	OutboxModeDebezium = "debezium"
+	// OutboxModeRelay publishes the outbox from the service itself.
+	OutboxModeRelay = "relay"
+)
+
+type OutboxConfig struct {
+	Mode string `yaml:"mode"`
+	// Interval and BatchSize only apply to the relay.
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batchSize"`
+	// DropSlot lets the relay drop the replication slot of the Debezium
+	// connector (Retention.Slot) on start. Only set it once the connector is
+	// gone for good: a connector that is paused or switched back loses its
+	// position when its slot is dropped.
+	DropSlot bool `yaml:"dropSlot"`
+
+	Retention OutboxRetentionConfig `yaml:"retention"`
+}
+
+// SlotToDrop returns the replication slot the relay is to drop on start, or
+// "" unless DropSlot is set.
+func (c OutboxConfig) SlotToDrop() string {
+	if !c.DropSlot {
+		return ""
+	}
+	return c.Retention.Slot
+}
+
+type OutboxRetentionConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	// After is how long a published message is kept, counted from its creation.
+	After     time.Duration `yaml:"after"`
+	BatchSize int           `yaml:"batchSize"`
+	// Archive moves removed messages to outbox_archive instead of dropping them.
+	Archive bool `yaml:"archive"`
+	// Slot is the replication slot of the Debezium connector. In debezium
+	// mode a message counts as published once the slot has confirmed it; in
+	// relay mode it is left alone unless DropSlot is set.
+	Slot string `yaml:"slot"`
+}
+
+// Carriers shipments can be handed to.
+const (
+	// CarrierFake makes up tracking numbers and moves shipments on by itself.
+	CarrierFake = "fake"
+)
+
+type CarrierConfig struct {
+	Name string `yaml:"name"`
+	// DispatchAfter and DeliverAfter are how long the fake carrier takes to
+	// dispatch and to deliver a shipment, counted from its creation.
+	DispatchAfter time.Duration `yaml:"dispatchAfter"`
+	DeliverAfter  time.Duration `yaml:"deliverAfter"`
+}
+
+type TrackingConfig struct {
+	// Interval is how often the carrier is asked about open shipments.
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batchSize"`
+}
+
+type AdminConfig struct {
+	// Tokens maps the name of each admin to the bearer token they sign in
+	// with. The name is recorded with the changes they make. Outside local
+	// they come from INVENTORY_ADMIN_TOKENS, see Load.
+	Tokens map[string]string `yaml:"tokens"`
+}
+
+// HasTokens reports whether any admin can sign in.
+func (a AdminConfig) HasTokens() bool {
+	for _, token := range a.Tokens {
+		if token != "" {
+			return true
+		}
+	}
+	return false
+}
+
 type Config struct {
 	Env             string        `yaml:"env"`
 	GracefulTimeout time.Duration `yaml:"gracefulTimeout"`
 
 	Inventory struct {
-		HTTP  HttpServerConfig `yaml:"http"`
-		DB    DBConfig         `yaml:"db"`
-		Kafka KafkaConfig      `yaml:"kafka"`
+		HTTP   HttpServerConfig `yaml:"http"`
+		GRPC   GrpcServerConfig `yaml:"grpc"`
+		DB     DBConfig         `yaml:"db"`
+		Kafka  KafkaConfig      `yaml:"kafka"`
+		Outbox OutboxConfig     `yaml:"outbox"`
+		Admin  AdminConfig      `yaml:"admin"`
+		// Debug is the internal listener of /debug/vars, kept off the
+		// public API.
+		Debug HttpServerConfig `yaml:"debug"`
 	} `yaml:"inventory"`
 
 	Payment struct {
 		HTTP  HttpServerConfig `yaml:"http"`
+		GRPC  GrpcServerConfig `yaml:"grpc"`
 		DB    DBConfig         `yaml:"db"`
 		Kafka KafkaConfig      `yaml:"kafka"`
 	} `yaml:"payment"`
 
 	Order struct {
-		HTTP  HttpServerConfig `yaml:"http"`
-		DB    DBConfig         `yaml:"db"`
-		Kafka KafkaConfig      `yaml:"kafka"`
+		HTTP   HttpServerConfig `yaml:"http"`
+		GRPC   GrpcServerConfig `yaml:"grpc"`
+		DB     DBConfig         `yaml:"db"`
+		Kafka  KafkaConfig      `yaml:"kafka"`
+		Saga   SagaConfig       `yaml:"saga"`
+		Expiry ExpiryConfig     `yaml:"expiry"`
+
+		Idempotency IdempotencyConfig `yaml:"idempotency"`
+		Outbox      OutboxConfig      `yaml:"outbox"`
+		// Debug is the internal listener of /debug/vars, kept off the
+		// public API.
+		Debug HttpServerConfig `yaml:"debug"`
 	} `yaml:"order"`
 
+	Shipping struct {
+		HTTP     HttpServerConfig `yaml:"http"`
+		DB       DBConfig         `yaml:"db"`
+		Kafka    KafkaConfig      `yaml:"kafka"`
+		Carrier  CarrierConfig    `yaml:"carrier"`
+		Tracking TrackingConfig   `yaml:"tracking"`
+		Outbox   OutboxConfig     `yaml:"outbox"`
+	} `yaml:"shipping"`
+
 	Storefront struct {
 		HTTP  HttpServerConfig `yaml:"http"`
 		Kafka KafkaConfig      `yaml:"kafka"`
+		// AsyncCreateOrder makes the order service answer POST /orders with
+		// 202 Accepted right away instead of waiting for the inventory reply.
+		AsyncCreateOrder bool `yaml:"asyncCreateOrder"`
+		// Transport selects the clients of the other services, TransportHTTP
+		// or TransportGRPC.
+		Transport string `yaml:"transport"`
+		// InventoryToken is the inventory admin token the storefront resets
+		// the products with. Outside local it comes from
+		// STOREFRONT_INVENTORY_TOKEN.
+		InventoryToken string `yaml:"inventoryToken"`
+		// Admin is the internal listener of the support pages and the
+		// product reset, kept off the public storefront.
+		Admin HttpServerConfig `yaml:"admin"`
 	} `yaml:"storefront"`
 }
 
@@ -105,11 +261,52 @@ func Load() (*Config, error) {
 	if err := yaml.Unmarshal(mergedYAML, &cfg); err != nil {
 		return nil, err
 	}
+	if err := loadSecrets(&cfg); err != nil {
+		return nil, err
+	}
 
-	log.Printf("Config loaded: %v", prettyPrint(cfg))
+	log.Printf("Config loaded: %v", prettyPrint(cfg.redacted()))
 	return &cfg, nil
 }
 
+// loadSecrets overrides the tokens of the config file with the ones in the
+// environment: INVENTORY_ADMIN_TOKENS holds comma separated name=token pairs
+// and STOREFRONT_INVENTORY_TOKEN the storefront's token.
+func loadSecrets(cfg *Config) error {
+	if env, ok := os.LookupEnv("INVENTORY_ADMIN_TOKENS"); ok {
+		tokens := make(map[string]string)
+		for _, pair := range strings.Split(env, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			name, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || name == "" || token == "" {
+				return fmt.Errorf("INVENTORY_ADMIN_TOKENS: want name=token, got %q", pair)
+			}
+			tokens[name] = token
+		}
+		cfg.Inventory.Admin.Tokens = tokens
+	}
+	if env, ok := os.LookupEnv("STOREFRONT_INVENTORY_TOKEN"); ok {
+		cfg.Storefront.InventoryToken = env
+	}
+	return nil
+}
+
+// redacted returns a copy of the config that is safe to log.
+func (c Config) redacted() Config {
+	const hidden = "REDACTED"
+	tokens := make(map[string]string, len(c.Inventory.Admin.Tokens))
+	for name := range c.Inventory.Admin.Tokens {
+		tokens[name] = hidden
+	}
+	c.Inventory.Admin.Tokens = tokens
+	if c.Storefront.InventoryToken != "" {
+		c.Storefront.InventoryToken = hidden
+	}
+	return c
+}
+
 func prettyPrint(v interface{}) string {
 	b, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
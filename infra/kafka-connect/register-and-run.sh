@@ -52,8 +52,22 @@ upsert_connector() {
   echo "[connect] Status for '$name': $status"
 }
 
-# Upsert known connectors from /configs
-upsert_connector /configs/order-outbox.json || true
+# Upsert known connectors from /configs. A service whose *_OUTBOX_MODE is relay
+# publishes its outbox itself and its connector must not run as well.
+ensure_outbox_connector() {
+  name="$1"
+  mode="$2"
+  if [ "$mode" = "relay" ]; then
+    echo "[connect] outbox mode relay, removing connector '$name'"
+    curl -sf -X DELETE http://localhost:8083/connectors/"$name" >/dev/null 2>&1 || true
+  else
+    upsert_connector /configs/"$name".json || true
+  fi
+}
+
+ensure_outbox_connector order-outbox "${ORDER_OUTBOX_MODE:-debezium}"
+ensure_outbox_connector inventory-outbox "${INVENTORY_OUTBOX_MODE:-debezium}"
+ensure_outbox_connector shipping-outbox "${SHIPPING_OUTBOX_MODE:-debezium}"
 
 # Show final connectors list
 final_list=$(curl -sf http://localhost:8083/connectors || true)
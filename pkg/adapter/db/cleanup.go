@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"time"
+)
+
+// outboxCleanupLockKey elects the single replica that cleans up at a given time.
+const outboxCleanupLockKey int64 = 0x6f7574626f78 // "outbox"
+
+// captureSlack covers the time between a message's created_at and the commit
+// of its transaction, which is when it reaches the write-ahead log.
+const captureSlack = time.Minute
+
+// Outbox metrics, served by expvar on the debug listener's /debug/vars.
+var (
+	outboxRemovedRows      = expvar.NewInt("outbox_rows_removed_total")
+	outboxDepth            = expvar.NewInt("outbox_depth")
+	outboxUnpublishedDepth = expvar.NewInt("outbox_unpublished_depth")
+)
+
+// outboxCheckpoint is a write-ahead log position and the time it was taken.
+type outboxCheckpoint struct {
+	lsn string
+	at  time.Time
+}
+
+// OutboxRetention configures OutboxCleaner.
+type OutboxRetention struct {
+	// Interval is how often the cleaner runs.
+	Interval time.Duration
+	// After is how long a published message is kept.
+	After     time.Duration
+	BatchSize int
+	// Archive moves removed messages to outbox_archive instead of dropping them.
+	Archive bool
+	// Slot is the replication slot of the Debezium connector.
+	Slot string
+}
+
+// OutboxCleaner periodically removes outbox messages that were published
+// longer ago than the retention allows, a batch at a time so that no lock is
+// held long.
+//
+// The relay records when it publishes a message. Debezium does not; in that
+// mode the cleaner takes a checkpoint of the write-ahead log position and, once
+// the connector's replication slot has confirmed it, marks the messages created
+// before it as published.
+type OutboxCleaner struct {
+	DB *DB
+	// Relayed is set when OutboxRelay publishes the outbox, which records
+	// when it publishes a message.
+	Relayed bool
+	Cfg     OutboxRetention
+
+	pending *outboxCheckpoint
+}
+
+func NewOutboxCleaner(db *DB, relayed bool, cfg OutboxRetention) *OutboxCleaner {
+	return &OutboxCleaner{DB: db, Relayed: relayed, Cfg: cfg}
+}
+
+func (c *OutboxCleaner) Start(ctx context.Context) error {
+	if c.Cfg.Interval <= 0 || c.Cfg.After <= 0 || c.Cfg.BatchSize <= 0 {
+		slog.Info("Outbox cleanup disabled")
+		return nil
+	}
+
+	slog.Info("Outbox cleanup started", "interval", c.Cfg.Interval, "retention", c.Cfg.After, "archive", c.Cfg.Archive)
+	ticker := time.NewTicker(c.Cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.clean(ctx)
+		}
+	}
+}
+
+func (c *OutboxCleaner) clean(ctx context.Context) {
+	var confirmed, removed int64
+	leader, err := c.DB.TryAdvisoryLock(ctx, outboxCleanupLockKey, func(ctx context.Context) error {
+		var err error
+		if !c.Relayed {
+			if confirmed, err = c.confirmCaptured(ctx); err != nil {
+				return err
+			}
+		}
+		removed, err = c.removePublished(ctx)
+		return err
+	})
+	outboxRemovedRows.Add(removed)
+	if err != nil {
+		slog.Error("Outbox cleanup failed:", "err", err)
+	} else if !leader {
+		slog.Debug("Outbox cleanup skipped, another replica is cleaning up")
+	} else if confirmed > 0 || removed > 0 {
+		slog.Info("Outbox cleanup finished", "confirmed", confirmed, "removed", removed, "archived", c.Cfg.Archive)
+	}
+
+	// every replica reports the depth, whether it cleaned up or not
+	total, unpublished, err := c.DB.OutboxDepth(ctx)
+	if err != nil {
+		slog.Warn("Failed to measure outbox depth:", "err", err)
+		return
+	}
+	outboxDepth.Set(total)
+	outboxUnpublishedDepth.Set(unpublished)
+}
+
+// confirmCaptured marks the messages captured by Debezium as published and
+// takes the next checkpoint.
+func (c *OutboxCleaner) confirmCaptured(ctx context.Context) (int64, error) {
+	var marked int64
+	if c.pending != nil {
+		ok, err := c.DB.SlotConfirmed(ctx, c.Cfg.Slot, c.pending.lsn)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			slog.Debug("Outbox checkpoint not confirmed by the replication slot yet", "slot", c.Cfg.Slot, "lsn", c.pending.lsn)
+			return 0, nil
+		}
+		for {
+			n, err := c.DB.MarkOutboxPublished(ctx, c.pending.at.Add(-captureSlack), c.Cfg.BatchSize)
+			marked += n
+			if err != nil {
+				return marked, err
+			}
+			if n < int64(c.Cfg.BatchSize) {
+				break
+			}
+		}
+	}
+
+	lsn, err := c.DB.CurrentWALPosition(ctx)
+	if err != nil {
+		return marked, err
+	}
+	c.pending = &outboxCheckpoint{lsn: lsn, at: time.Now()}
+	return marked, nil
+}
+
+func (c *OutboxCleaner) removePublished(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-c.Cfg.After)
+	var removed int64
+	for {
+		n, err := c.DB.RemovePublishedOutbox(ctx, before, c.Cfg.BatchSize, c.Cfg.Archive)
+		removed += n
+		if err != nil || n < int64(c.Cfg.BatchSize) {
+			return removed, err
+		}
+		if ctx.Err() != nil {
+			return removed, ctx.Err()
+		}
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Config struct {
@@ -19,6 +20,16 @@ type Config struct {
 
 type DB struct {
 	conn *sql.DB
+	dsn  string
+}
+
+// Notification is a message received on a Postgres LISTEN channel.
+type Notification struct {
+	Channel string
+	Payload string
+	// Reconnected is set instead of a payload after the listener lost its
+	// connection, notifications sent in the meantime are gone.
+	Reconnected bool
 }
 
 func Connect(cfg Config) (*DB, error) {
@@ -26,7 +37,7 @@ func Connect(cfg Config) (*DB, error) {
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name)
 
 	var err error
-	db := &DB{}
+	db := &DB{dsn: psqlInfo}
 	db.conn, err = sql.Open("postgres", psqlInfo)
 	if err != nil {
 		return nil, err
@@ -47,3 +58,68 @@ func (db *DB) Shutdown(ctx context.Context) error {
 func (db *DB) GetConn() *sql.DB {
 	return db.conn
 }
+
+// Listen subscribes to a Postgres NOTIFY channel on a dedicated connection.
+// The returned channel is closed when ctx is done.
+func (db *DB) Listen(ctx context.Context, channel string) (<-chan Notification, error) {
+	l := pq.NewListener(db.dsn, 100*time.Millisecond, 10*time.Second, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("Database listener event", "channel", channel, "event", ev, "err", err)
+		}
+	})
+	if err := l.Listen(channel); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	out := make(chan Notification, 64)
+	go func() {
+		defer close(out)
+		defer l.Close()
+		for {
+			var n Notification
+			select {
+			case <-ctx.Done():
+				return
+			case pn := <-l.Notify:
+				n = Notification{Channel: channel, Reconnected: pn == nil}
+				if pn != nil {
+					n.Payload = pn.Extra
+				}
+			case <-time.After(90 * time.Second):
+				// keep the idle connection alive
+				go l.Ping()
+				continue
+			}
+			select {
+			case out <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	slog.Info("Database listener started", "channel", channel)
+	return out, nil
+}
+
+// TryAdvisoryLock runs fn while holding the transaction-level advisory lock key.
+// It returns false without calling fn when another session holds the lock, which
+// lets several replicas elect a single runner for a background job.
+func (db *DB) TryAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// the lock is only used for mutual exclusion, nothing is written in tx
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, key).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
@@ -2,4 +2,13 @@ module github.com/axmz/go-saga-microservices/lib/adapter/db
 
 go 1.24.4
 
-require github.com/lib/pq v1.10.9
+require (
+	github.com/google/uuid v1.6.0
+	github.com/lib/pq v1.10.9
+	github.com/segmentio/kafka-go v0.4.48
+)
+
+require (
+	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+)
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// HandleOnce makes event consumption idempotent. It runs fn in a transaction
+// that also records messageID in the inbox table as processed by consumer, so
+// the business update and the inbox entry commit or roll back together. When
+// the message was processed before, fn is not called and HandleOnce returns
+// false.
+//
+// A message without an id cannot be deduplicated; fn then runs in a
+// transaction of its own.
+//
+// The inbox table must exist in the service's database:
+//
+//	CREATE TABLE inbox (
+//	    consumer TEXT NOT NULL,
+//	    message_id TEXT NOT NULL,
+//	    processed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+//	    PRIMARY KEY (consumer, message_id)
+//	);
+func (db *DB) HandleOnce(ctx context.Context, consumer, messageID string, fn func(tx *sql.Tx) error) (bool, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if messageID != "" {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO inbox (consumer, message_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, consumer, messageID)
+		if err != nil {
+			_ = tx.Rollback()
+			return false, fmt.Errorf("record inbox message: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			_ = tx.Rollback()
+			return false, nil
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,242 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// OutboxMessage is an event in the outbox table, written in the same
+// transaction as the change it reports. Debezium or OutboxRelay publishes it
+// and OutboxCleaner removes it once published.
+type OutboxMessage struct {
+	ID            uuid.UUID       `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Headers       map[string]any  `json:"headers"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// InsertOutbox writes msg to the outbox in tx, filling in a missing id and
+// creation time.
+func (db *DB) InsertOutbox(ctx context.Context, tx *sql.Tx, msg OutboxMessage) error {
+	if msg.ID == uuid.Nil {
+		msg.ID = uuid.New()
+	}
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	if msg.Headers == nil {
+		msg.Headers = map[string]any{}
+	}
+	b, _ := json.Marshal(msg.Headers)
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO outbox (id, aggregate_type, aggregate_id, event_type, payload, headers, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7)`,
+		msg.ID, msg.AggregateType, msg.AggregateID, msg.EventType, []byte(msg.Payload), string(b), msg.CreatedAt,
+	)
+	return err
+}
+
+// outboxRelayLockKey elects the single replica that relays at a given time.
+const outboxRelayLockKey int64 = 0x72656c6179 // "relay"
+
+// RelayOutbox claims up to limit unpublished messages, oldest first, hands them
+// to publish and marks them published when it succeeds. Only one relay claims
+// messages at a time: were replicas to relay side by side, a later event of an
+// aggregate could reach Kafka before an earlier one another replica still
+// holds. Another replica's relay returns 0 without publishing. A crash between
+// publish and commit publishes the messages again; consumers deduplicate them
+// by event id.
+func (db *DB) RelayOutbox(ctx context.Context, limit int, publish func(ctx context.Context, msgs []OutboxMessage) error) (int, error) {
+	tx, err := db.conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxRelayLockKey).Scan(&locked); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if !locked {
+		_ = tx.Rollback()
+		return 0, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, created_at
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $1
+		FOR UPDATE
+	`, limit)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("claim outbox messages: %w", err)
+	}
+
+	var (
+		msgs []OutboxMessage
+		ids  []string
+	)
+	for rows.Next() {
+		var (
+			msg     OutboxMessage
+			payload []byte
+			headers []byte
+		)
+		if err := rows.Scan(&msg.ID, &msg.AggregateType, &msg.AggregateID, &msg.EventType, &payload, &headers, &msg.CreatedAt); err != nil {
+			rows.Close()
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("scan outbox message: %w", err)
+		}
+		msg.Payload = payload
+		if len(headers) > 0 {
+			if err := json.Unmarshal(headers, &msg.Headers); err != nil {
+				rows.Close()
+				_ = tx.Rollback()
+				return 0, fmt.Errorf("decode outbox headers of %s: %w", msg.ID, err)
+			}
+		}
+		msgs = append(msgs, msg)
+		ids = append(ids, msg.ID.String())
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if len(msgs) == 0 {
+		_ = tx.Rollback()
+		return 0, nil
+	}
+
+	if err := publish(ctx, msgs); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE outbox SET published_at = now() WHERE id = ANY($1::uuid[])
+	`, pq.Array(ids)); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("mark outbox messages published: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(msgs), nil
+}
+
+// MarkOutboxPublished sets published_at on up to limit unpublished messages
+// created before the given time. It is for messages published by a means that
+// does not record it, like Debezium.
+func (db *DB) MarkOutboxPublished(ctx context.Context, createdBefore time.Time, limit int) (int64, error) {
+	res, err := db.conn.ExecContext(ctx, `
+		UPDATE outbox SET published_at = now()
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE published_at IS NULL AND created_at < $1
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`, createdBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("mark outbox messages published: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// RemovePublishedOutbox deletes up to limit published messages created before
+// the given time, oldest first. With archive set they are moved to
+// outbox_archive instead.
+func (db *DB) RemovePublishedOutbox(ctx context.Context, createdBefore time.Time, limit int, archive bool) (int64, error) {
+	del := `
+		DELETE FROM outbox
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE published_at IS NOT NULL AND created_at < $1
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)`
+	q := del
+	if archive {
+		q = `
+		WITH moved AS (` + del + `
+			RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, created_at, published_at
+		)
+		INSERT INTO outbox_archive (id, aggregate_type, aggregate_id, event_type, payload, headers, created_at, published_at)
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, created_at, published_at FROM moved
+		ON CONFLICT (id) DO NOTHING`
+	}
+
+	res, err := db.conn.ExecContext(ctx, q, createdBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("remove published outbox messages: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// OutboxDepth returns how many messages the outbox holds and how many of them
+// are not published yet.
+func (db *DB) OutboxDepth(ctx context.Context) (total, unpublished int64, err error) {
+	err = db.conn.QueryRowContext(ctx, `
+		SELECT count(*), count(*) FILTER (WHERE published_at IS NULL) FROM outbox
+	`).Scan(&total, &unpublished)
+	return total, unpublished, err
+}
+
+// CurrentWALPosition returns the current write-ahead log position.
+func (db *DB) CurrentWALPosition(ctx context.Context) (string, error) {
+	var lsn string
+	err := db.conn.QueryRowContext(ctx, `SELECT pg_current_wal_lsn()::text`).Scan(&lsn)
+	return lsn, err
+}
+
+// SlotConfirmed reports whether the consumer of the replication slot has
+// confirmed the write-ahead log up to lsn. A missing slot confirms nothing.
+func (db *DB) SlotConfirmed(ctx context.Context, slot, lsn string) (bool, error) {
+	var confirmed sql.NullBool
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT confirmed_flush_lsn >= $2::pg_lsn
+		FROM pg_replication_slots
+		WHERE slot_name = $1
+	`, slot, lsn).Scan(&confirmed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return confirmed.Valid && confirmed.Bool, nil
+}
+
+// DropReplicationSlot drops the replication slot unless a consumer is
+// attached to it, and reports whether it did. A slot nobody reads retains the
+// write-ahead log until the disk fills up.
+func (db *DB) DropReplicationSlot(ctx context.Context, slot string) (bool, error) {
+	res, err := db.conn.ExecContext(ctx, `
+		SELECT pg_drop_replication_slot(slot_name)
+		FROM pg_replication_slots
+		WHERE slot_name = $1 AND NOT active
+	`, slot)
+	if err != nil {
+		return false, fmt.Errorf("drop replication slot %s: %w", slot, err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
+	"github.com/segmentio/kafka-go"
+)
+
+// OutboxRelay drains the outbox table into Kafka without Kafka Connect.
+// Messages are published exactly as the Debezium outbox router publishes
+// them: the raw protobuf payload as value, the aggregate id as key, and the
+// content type, message id and event type as headers.
+type OutboxRelay struct {
+	DB        *DB
+	Writer    *kafka.Writer
+	Interval  time.Duration
+	BatchSize int
+	// Slot, when set, is the replication slot of a Debezium connector that
+	// was removed for good. The relay drops it on start, as nothing reads it
+	// any more; an empty Slot leaves every slot alone.
+	Slot string
+}
+
+func NewOutboxRelay(db *DB, addr, topic string, interval time.Duration, batchSize int, slot string) *OutboxRelay {
+	return &OutboxRelay{
+		DB: db,
+		Writer: &kafka.Writer{
+			AllowAutoTopicCreation: true,
+			Addr:                   kafka.TCP(addr),
+			Topic:                  topic,
+			// partition by key like Kafka Connect does, so the events of an
+			// aggregate stay in order
+			Balancer: &kafka.Murmur2Balancer{},
+			// a message is only marked published once Kafka has it
+			RequiredAcks: kafka.RequireAll,
+		},
+		Interval:  interval,
+		BatchSize: batchSize,
+		Slot:      slot,
+	}
+}
+
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	if r.Interval <= 0 || r.BatchSize <= 0 {
+		slog.Info("Outbox relay disabled")
+		return nil
+	}
+
+	if r.Slot != "" {
+		dropped, err := r.DB.DropReplicationSlot(ctx, r.Slot)
+		if err != nil {
+			slog.Warn("Failed to drop the Debezium replication slot:", "slot", r.Slot, "err", err)
+		} else if dropped {
+			slog.Info("Debezium replication slot dropped", "slot", r.Slot)
+		}
+	}
+
+	slog.Info("Outbox relay started", "topic", r.Writer.Topic, "interval", r.Interval, "batchSize", r.BatchSize)
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			r.drain(ctx)
+		}
+	}
+}
+
+func (r *OutboxRelay) Shutdown(ctx context.Context) error {
+	return r.Writer.Close()
+}
+
+// drain relays full batches back to back until the outbox is empty.
+func (r *OutboxRelay) drain(ctx context.Context) {
+	for {
+		n, err := r.DB.RelayOutbox(ctx, r.BatchSize, r.publish)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Outbox relay failed:", "err", err)
+			}
+			return
+		}
+		if n > 0 {
+			slog.Debug("Outbox messages relayed", "count", n)
+		}
+		if n < r.BatchSize {
+			return
+		}
+	}
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, msgs []OutboxMessage) error {
+	out := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		out[i] = kafka.Message{
+			Key:     []byte(msg.AggregateID),
+			Value:   msg.Payload,
+			Headers: codec.Headers(msg.ID.String(), msg.EventType),
+		}
+	}
+	return r.Writer.WriteMessages(ctx, out...)
+}
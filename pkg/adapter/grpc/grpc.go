@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type Config struct {
+	Host string
+	Port string
+}
+
+// Server serves the gRPC API of a service on its own port, next to the HTTP
+// server. Services register their implementations on Router before Run.
+type Server struct {
+	Router *grpc.Server
+	Addr   string
+	health *health.Server
+}
+
+func NewServer(cfg Config) (*Server, error) {
+	addr := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+	r := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(LoggingUnaryInterceptor),
+		grpc.ChainStreamInterceptor(LoggingStreamInterceptor),
+	)
+
+	// health checks and reflection for grpcurl and load balancers
+	h := health.NewServer()
+	healthpb.RegisterHealthServer(r, h)
+	reflection.Register(r)
+
+	slog.Info("gRPC server initialized", "addr", addr)
+	return &Server{Router: r, Addr: addr, health: h}, nil
+}
+
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("gRPC server Listen: %v", err)
+	}
+
+	slog.Info("Starting gRPC server", "addr", s.Addr)
+	if err := s.Router.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("gRPC server Serve: %v", err)
+	}
+
+	return nil
+}
+
+// Shutdown lets running calls finish, and cuts them off once ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.Router.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.Router.Stop()
+		return ctx.Err()
+	}
+}
+
+// Dial creates a client connection to a gRPC server of another service. The
+// connection is established lazily, on the first call.
+func Dial(addr string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
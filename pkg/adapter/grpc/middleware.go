@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func LoggingUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	slog.Info("grpc request",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"remote", remoteAddr(ctx),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return resp, err
+}
+
+func LoggingStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	slog.Info("grpc stream",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"remote", remoteAddr(ss.Context()),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return err
+}
+
+func remoteAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,15 @@
+package http
+
+import "strings"
+
+// ETagMatches reports whether an If-None-Match value names etag. It compares
+// weakly, as If-None-Match does, so W/"1" matches "1".
+func ETagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
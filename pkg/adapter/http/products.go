@@ -0,0 +1,42 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+)
+
+// ParseGetProductsRequest reads a product listing from the query string of
+// GET /products and its If-None-Match header. It only checks that the numbers
+// parse; the inventory service validates the rest.
+func ParseGetProductsRequest(r *http.Request) (*httppb.GetProductsRequest, error) {
+	q := r.URL.Query()
+	req := &httppb.GetProductsRequest{
+		Status:      q.Get("status"),
+		Q:           q.Get("q"),
+		Sort:        q.Get("sort"),
+		Cursor:      q.Get("cursor"),
+		IfNoneMatch: r.Header.Get("If-None-Match"),
+	}
+	var err error
+	if v := q.Get("min_price"); v != "" {
+		if req.MinPrice, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("invalid min_price: %s", v)
+		}
+	}
+	if v := q.Get("max_price"); v != "" {
+		if req.MaxPrice, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("invalid max_price: %s", v)
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit: %s", v)
+		}
+		req.Limit = int32(limit)
+	}
+	return req, nil
+}
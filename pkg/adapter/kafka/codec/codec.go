@@ -0,0 +1,126 @@
+// Package codec encodes and decodes the event envelopes exchanged over Kafka.
+// Every publisher and consumer goes through it, so all events on the wire are
+// protobuf envelopes labelled with their content type.
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+)
+
+// Headers set on every event message.
+const (
+	HeaderContentType = "content-type"
+	HeaderEventID     = "id"
+	HeaderEventType   = "event_type"
+)
+
+// ContentTypeProtobuf is the only encoding events are published in.
+const ContentTypeProtobuf = "application/x-protobuf"
+
+// Envelope is any of the event envelopes.
+type Envelope interface {
+	*events.OrderEventEnvelope |
+		*events.InventoryEventEnvelope |
+		*events.PaymentEventEnvelope |
+		*events.ShippingEventEnvelope
+	proto.Message
+	GetEventId() string
+}
+
+// ErrDecode is returned for a message that is not an envelope of the expected
+// type, because of its content type or because its payload does not parse.
+var ErrDecode = errors.New("cannot decode event")
+
+type ErrDecodeMessage struct {
+	Topic  string
+	Reason string
+}
+
+func (e *ErrDecodeMessage) Error() string {
+	return fmt.Sprintf("cannot decode event from %s: %s", e.Topic, e.Reason)
+}
+
+func (e *ErrDecodeMessage) Unwrap() error {
+	return ErrDecode
+}
+
+func NewErrDecode(topic, reason string) error {
+	return &ErrDecodeMessage{Topic: topic, Reason: reason}
+}
+
+// Marshal encodes env as the payload of an event message, for senders like
+// an outbox that build the message themselves.
+func Marshal[E Envelope](env E) ([]byte, error) {
+	return proto.Marshal(env)
+}
+
+// Encode builds the message publishing env under key.
+func Encode[E Envelope](key string, env E) (kafka.Message, error) {
+	value, err := Marshal(env)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	return kafka.Message{
+		Key:     []byte(key),
+		Value:   value,
+		Headers: Headers(env.GetEventId(), EventType(env)),
+	}, nil
+}
+
+// Headers returns the headers of an event message with the given id and type.
+func Headers(eventID, eventType string) []kafka.Header {
+	return []kafka.Header{
+		{Key: HeaderContentType, Value: []byte(ContentTypeProtobuf)},
+		{Key: HeaderEventID, Value: []byte(eventID)},
+		{Key: HeaderEventType, Value: []byte(eventType)},
+	}
+}
+
+// Decode parses the envelope of type E from m. A message without a content
+// type was published before the codec labelled messages and holds a bare
+// protobuf envelope, so it is decoded as protobuf too. A message with another
+// content type, or with a payload that is not an E, is an *ErrDecodeMessage.
+func Decode[E Envelope](m kafka.Message) (E, error) {
+	var env E
+	if ct := Header(m, HeaderContentType); ct != "" && ct != ContentTypeProtobuf {
+		return env, NewErrDecode(m.Topic, fmt.Sprintf("unsupported content type %q", ct))
+	}
+
+	env = env.ProtoReflect().Type().New().Interface().(E)
+	if err := proto.Unmarshal(m.Value, env); err != nil {
+		return env, NewErrDecode(m.Topic, err.Error())
+	}
+	return env, nil
+}
+
+// EventType names the event set in env after its message, without an "Event"
+// suffix: OrderCreated, InventoryReservationFailed and so on. It is empty when
+// no event is set.
+func EventType[E Envelope](env E) string {
+	msg := env.ProtoReflect()
+	oneof := msg.Descriptor().Oneofs().ByName("event")
+	if oneof == nil {
+		return ""
+	}
+	field := msg.WhichOneof(oneof)
+	if field == nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(field.Message().Name()), "Event")
+}
+
+// Header returns the value of the first header named key, or "".
+func Header(m kafka.Message, key string) string {
+	for _, h := range m.Headers {
+		if strings.EqualFold(h.Key, key) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
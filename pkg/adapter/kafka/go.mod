@@ -2,7 +2,10 @@ module github.com/axmz/go-saga-microservices/lib/adapter/kafka
 
 go 1.24.4
 
-require github.com/segmentio/kafka-go v0.4.48
+require (
+	github.com/segmentio/kafka-go v0.4.48
+	google.golang.org/protobuf v1.36.6
+)
 
 require (
 	github.com/klauspost/compress v1.15.9 // indirect
@@ -2,8 +2,14 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"strconv"
+	"time"
 
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -15,8 +21,9 @@ type Config struct {
 }
 
 type Broker struct {
-	Writer *kafka.Writer
-	Reader *kafka.Reader
+	Writer     *kafka.Writer
+	Reader     *kafka.Reader
+	DeadLetter *DeadLetter
 }
 
 func Init(cfg Config) (*Broker, error) {
@@ -40,31 +47,166 @@ func Init(cfg Config) (*Broker, error) {
 
 	slog.Info("Kafka initialized", "addr", cfg.Addr, "producerTopic", cfg.ProducerTopic, "consumerTopic", cfg.GroupTopics, "groupID", cfg.GroupID)
 	return &Broker{
-		Writer: writer,
-		Reader: reader,
+		Writer:     writer,
+		Reader:     reader,
+		DeadLetter: NewDeadLetter(Addr),
 	}, nil
 }
 
-func (kc *Broker) ListenAndHandle(ctx context.Context, handler func(kafka.Message)) error {
+// ErrPermanent marks a handler error that no retry can fix. Messages failing
+// with it, or with codec.ErrDecode, are dead-lettered right away.
+var ErrPermanent = errors.New("permanent handler error")
+
+type ErrPermanentCause struct {
+	Err error
+}
+
+func (e *ErrPermanentCause) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ErrPermanentCause) Unwrap() []error {
+	return []error{ErrPermanent, e.Err}
+}
+
+func NewErrPermanent(err error) error {
+	return &ErrPermanentCause{Err: err}
+}
+
+// Backoff between two attempts at a message that failed.
+const (
+	minRetryBackoff = 100 * time.Millisecond
+	maxRetryBackoff = 30 * time.Second
+)
+
+// ListenAndHandle reads the group topics and hands every message to handler
+// until ctx is done or the reader is closed. A message is only committed once
+// it was handled or dead-lettered, so that one in flight when the consumer
+// stops is delivered again. Transient handler errors are retried with backoff,
+// holding up the partition; messages that cannot be decoded or fail with
+// ErrPermanent are parked on the dead-letter topic instead.
+func (kc *Broker) ListenAndHandle(ctx context.Context, handler func(context.Context, kafka.Message) error) error {
 	for {
-		m, err := kc.Reader.ReadMessage(ctx)
+		m, err := kc.Reader.FetchMessage(ctx)
 		if err != nil {
+			if errors.Is(err, kafka.ErrGroupClosed) ||
+				errors.Is(err, io.EOF) ||
+				ctx.Err() != nil {
+				return err
+			}
+			slog.Warn("Kafka read error:", "err", err)
+			continue
+		}
+		if err := kc.handle(ctx, m, handler); err != nil {
 			return err
 		}
-		handler(m)
+		if err := kc.Reader.CommitMessages(ctx, m); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return err
+			}
+			// the message comes again after a rebalance, consumers skip it by event id
+			slog.Warn("Kafka commit error:", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "err", err)
+		}
+	}
+}
+
+// handle runs handler on m until it succeeds or m is dead-lettered, backing
+// off between attempts. It only fails when ctx is done first, in which case m
+// must not be committed.
+func (kc *Broker) handle(ctx context.Context, m kafka.Message, handler func(context.Context, kafka.Message) error) error {
+	var cause error
+	for backoff := minRetryBackoff; ; backoff = min(2*backoff, maxRetryBackoff) {
+		if cause == nil {
+			err := handler(ctx, m)
+			if err == nil {
+				return nil
+			}
+			if errors.Is(err, codec.ErrDecode) || errors.Is(err, ErrPermanent) {
+				cause = err
+			} else {
+				slog.Warn("Kafka handler error, retrying:", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "backoff", backoff, "err", err)
+			}
+		}
+		if cause != nil {
+			err := kc.DeadLetter.Send(ctx, m, cause)
+			if err == nil {
+				return nil
+			}
+			slog.Error("Failed to dead-letter message, retrying:", "backoff", backoff, "err", err, "cause", cause)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
 	}
 }
 
 func (kc *Broker) Shutdown(ctx context.Context) error {
-	var errWriter, errReader error
+	var errWriter, errReader, errDeadLetter error
 	if kc.Writer != nil {
 		errWriter = kc.Writer.Close()
 	}
 	if kc.Reader != nil {
 		errReader = kc.Reader.Close()
 	}
-	if errWriter != nil {
-		return errWriter
+	if kc.DeadLetter != nil {
+		errDeadLetter = kc.DeadLetter.Writer.Close()
+	}
+	return errors.Join(errWriter, errReader, errDeadLetter)
+}
+
+// DeadLetterSuffix is appended to a topic to name its dead-letter topic.
+const DeadLetterSuffix = ".dlq"
+
+// Headers describing why and from where a message was dead-lettered.
+const (
+	HeaderDeadLetterError     = "dlq-error"
+	HeaderDeadLetterTopic     = "dlq-topic"
+	HeaderDeadLetterPartition = "dlq-partition"
+	HeaderDeadLetterOffset    = "dlq-offset"
+)
+
+// DeadLetter parks messages a consumer cannot process on the dead-letter topic
+// of the topic they came from, where they can be inspected and replayed.
+type DeadLetter struct {
+	Writer *kafka.Writer
+}
+
+// NewDeadLetter partitions by key with the partitioner of the outbox relays
+// and Debezium, so a message keeps its partition number on the dead-letter
+// topic.
+func NewDeadLetter(addr string) *DeadLetter {
+	return &DeadLetter{
+		Writer: &kafka.Writer{
+			AllowAutoTopicCreation: true,
+			Addr:                   kafka.TCP(addr),
+			Balancer:               &kafka.Murmur2Balancer{},
+			RequiredAcks:           kafka.RequireAll,
+		},
+	}
+}
+
+// Send copies m, headers included, to the dead-letter topic of m.Topic and
+// records cause and the position of m in the headers.
+func (d *DeadLetter) Send(ctx context.Context, m kafka.Message, cause error) error {
+	headers := append([]kafka.Header{}, m.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: HeaderDeadLetterError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderDeadLetterTopic, Value: []byte(m.Topic)},
+		kafka.Header{Key: HeaderDeadLetterPartition, Value: []byte(strconv.Itoa(m.Partition))},
+		kafka.Header{Key: HeaderDeadLetterOffset, Value: []byte(strconv.FormatInt(m.Offset, 10))},
+	)
+	err := d.Writer.WriteMessages(ctx, kafka.Message{
+		Topic:   m.Topic + DeadLetterSuffix,
+		Key:     m.Key,
+		Value:   m.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("dead-letter message from %s: %w", m.Topic, err)
 	}
-	return errReader
+	slog.Warn("Message dead-lettered", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "err", cause)
+	return nil
 }
@@ -22,10 +22,14 @@ const (
 )
 
 type Item struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Quantity int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// in minor units of currency, cents for USD
+	UnitPriceMinor int64  `protobuf:"varint,5,opt,name=unit_price_minor,json=unitPriceMinor,proto3" json:"unit_price_minor,omitempty"`
+	Currency       string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Item) Reset() {
@@ -65,19 +69,139 @@ func (x *Item) GetId() string {
 	return ""
 }
 
+func (x *Item) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Item) GetUnitPriceMinor() int64 {
+	if x != nil {
+		return x.UnitPriceMinor
+	}
+	return 0
+}
+
+func (x *Item) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Line1         string                 `protobuf:"bytes,2,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,3,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Address) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
 type OrderEventEnvelope struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Event:
 	//
 	//	*OrderEventEnvelope_OrderCreated
-	Event         isOrderEventEnvelope_Event `protobuf_oneof:"event"`
+	//	*OrderEventEnvelope_OrderExpired
+	//	*OrderEventEnvelope_OrderCancelled
+	//	*OrderEventEnvelope_RefundRequested
+	//	*OrderEventEnvelope_OrderRefunded
+	Event isOrderEventEnvelope_Event `protobuf_oneof:"event"`
+	// event_id identifies the event across redeliveries, consumers use it to
+	// skip events they already processed
+	EventId       string `protobuf:"bytes,15,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *OrderEventEnvelope) Reset() {
 	*x = OrderEventEnvelope{}
-	mi := &file_events_proto_msgTypes[1]
+	mi := &file_events_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -89,7 +213,7 @@ func (x *OrderEventEnvelope) String() string {
 func (*OrderEventEnvelope) ProtoMessage() {}
 
 func (x *OrderEventEnvelope) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[1]
+	mi := &file_events_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -102,58 +226,1481 @@ func (x *OrderEventEnvelope) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderEventEnvelope.ProtoReflect.Descriptor instead.
 func (*OrderEventEnvelope) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{1}
+	return file_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderEventEnvelope) GetEvent() isOrderEventEnvelope_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *OrderEventEnvelope) GetOrderCreated() *OrderCreatedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*OrderEventEnvelope_OrderCreated); ok {
+			return x.OrderCreated
+		}
+	}
+	return nil
+}
+
+func (x *OrderEventEnvelope) GetOrderExpired() *OrderExpiredEvent {
+	if x != nil {
+		if x, ok := x.Event.(*OrderEventEnvelope_OrderExpired); ok {
+			return x.OrderExpired
+		}
+	}
+	return nil
+}
+
+func (x *OrderEventEnvelope) GetOrderCancelled() *OrderCancelledEvent {
+	if x != nil {
+		if x, ok := x.Event.(*OrderEventEnvelope_OrderCancelled); ok {
+			return x.OrderCancelled
+		}
+	}
+	return nil
+}
+
+func (x *OrderEventEnvelope) GetRefundRequested() *RefundRequestedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*OrderEventEnvelope_RefundRequested); ok {
+			return x.RefundRequested
+		}
+	}
+	return nil
+}
+
+func (x *OrderEventEnvelope) GetOrderRefunded() *OrderRefundedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*OrderEventEnvelope_OrderRefunded); ok {
+			return x.OrderRefunded
+		}
+	}
+	return nil
+}
+
+func (x *OrderEventEnvelope) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+type isOrderEventEnvelope_Event interface {
+	isOrderEventEnvelope_Event()
+}
+
+type OrderEventEnvelope_OrderCreated struct {
+	OrderCreated *OrderCreatedEvent `protobuf:"bytes,1,opt,name=order_created,json=orderCreated,proto3,oneof"`
+}
+
+type OrderEventEnvelope_OrderExpired struct {
+	OrderExpired *OrderExpiredEvent `protobuf:"bytes,2,opt,name=order_expired,json=orderExpired,proto3,oneof"`
+}
+
+type OrderEventEnvelope_OrderCancelled struct {
+	OrderCancelled *OrderCancelledEvent `protobuf:"bytes,3,opt,name=order_cancelled,json=orderCancelled,proto3,oneof"`
+}
+
+type OrderEventEnvelope_RefundRequested struct {
+	RefundRequested *RefundRequestedEvent `protobuf:"bytes,4,opt,name=refund_requested,json=refundRequested,proto3,oneof"`
+}
+
+type OrderEventEnvelope_OrderRefunded struct {
+	OrderRefunded *OrderRefundedEvent `protobuf:"bytes,5,opt,name=order_refunded,json=orderRefunded,proto3,oneof"`
+}
+
+func (*OrderEventEnvelope_OrderCreated) isOrderEventEnvelope_Event() {}
+
+func (*OrderEventEnvelope_OrderExpired) isOrderEventEnvelope_Event() {}
+
+func (*OrderEventEnvelope_OrderCancelled) isOrderEventEnvelope_Event() {}
+
+func (*OrderEventEnvelope_RefundRequested) isOrderEventEnvelope_Event() {}
+
+func (*OrderEventEnvelope_OrderRefunded) isOrderEventEnvelope_Event() {}
+
+type OrderCreatedEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items           []*Item                `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	SubtotalMinor   int64                  `protobuf:"varint,9,opt,name=subtotal_minor,json=subtotalMinor,proto3" json:"subtotal_minor,omitempty"`
+	TotalMinor      int64                  `protobuf:"varint,10,opt,name=total_minor,json=totalMinor,proto3" json:"total_minor,omitempty"`
+	Currency        string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	CustomerId      string                 `protobuf:"bytes,6,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Email           string                 `protobuf:"bytes,7,opt,name=email,proto3" json:"email,omitempty"`
+	ShippingAddress *Address               `protobuf:"bytes,8,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *OrderCreatedEvent) Reset() {
+	*x = OrderCreatedEvent{}
+	mi := &file_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreatedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreatedEvent) ProtoMessage() {}
+
+func (x *OrderCreatedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreatedEvent.ProtoReflect.Descriptor instead.
+func (*OrderCreatedEvent) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderCreatedEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderCreatedEvent) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderCreatedEvent) GetSubtotalMinor() int64 {
+	if x != nil {
+		return x.SubtotalMinor
+	}
+	return 0
+}
+
+func (x *OrderCreatedEvent) GetTotalMinor() int64 {
+	if x != nil {
+		return x.TotalMinor
+	}
+	return 0
+}
+
+func (x *OrderCreatedEvent) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *OrderCreatedEvent) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *OrderCreatedEvent) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *OrderCreatedEvent) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+type OrderExpiredEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderExpiredEvent) Reset() {
+	*x = OrderExpiredEvent{}
+	mi := &file_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderExpiredEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderExpiredEvent) ProtoMessage() {}
+
+func (x *OrderExpiredEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderExpiredEvent.ProtoReflect.Descriptor instead.
+func (*OrderExpiredEvent) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderExpiredEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type OrderCancelledEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCancelledEvent) Reset() {
+	*x = OrderCancelledEvent{}
+	mi := &file_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCancelledEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCancelledEvent) ProtoMessage() {}
+
+func (x *OrderCancelledEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCancelledEvent.ProtoReflect.Descriptor instead.
+func (*OrderCancelledEvent) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderCancelledEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Asks payment to give back amount for the given lines of a paid order. full
+// is set when nothing of the order is left unrefunded afterwards.
+type RefundRequestedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RefundId      string                 `protobuf:"bytes,2,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	Items         []*Item                `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	AmountMinor   int64                  `protobuf:"varint,8,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Full          bool                   `protobuf:"varint,6,opt,name=full,proto3" json:"full,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundRequestedEvent) Reset() {
+	*x = RefundRequestedEvent{}
+	mi := &file_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundRequestedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundRequestedEvent) ProtoMessage() {}
+
+func (x *RefundRequestedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundRequestedEvent.ProtoReflect.Descriptor instead.
+func (*RefundRequestedEvent) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RefundRequestedEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RefundRequestedEvent) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *RefundRequestedEvent) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *RefundRequestedEvent) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+func (x *RefundRequestedEvent) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RefundRequestedEvent) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+func (x *RefundRequestedEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// The refund went through; inventory takes the items back into stock. full is
+// set when nothing of the order is left unrefunded, shipping then gives up the
+// shipment.
+type OrderRefundedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RefundId      string                 `protobuf:"bytes,2,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	Items         []*Item                `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Full          bool                   `protobuf:"varint,4,opt,name=full,proto3" json:"full,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderRefundedEvent) Reset() {
+	*x = OrderRefundedEvent{}
+	mi := &file_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderRefundedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderRefundedEvent) ProtoMessage() {}
+
+func (x *OrderRefundedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderRefundedEvent.ProtoReflect.Descriptor instead.
+func (*OrderRefundedEvent) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OrderRefundedEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderRefundedEvent) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *OrderRefundedEvent) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderRefundedEvent) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+type InventoryEventEnvelope struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*InventoryEventEnvelope_ReservationSucceeded
+	//	*InventoryEventEnvelope_ReservationFailed
+	//	*InventoryEventEnvelope_ReservationReleased
+	//	*InventoryEventEnvelope_ProductCreated
+	//	*InventoryEventEnvelope_ProductUpdated
+	//	*InventoryEventEnvelope_PriceChanged
+	Event isInventoryEventEnvelope_Event `protobuf_oneof:"event"`
+	// event_id identifies the event across redeliveries, consumers use it to
+	// skip events they already processed
+	EventId       string `protobuf:"bytes,15,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryEventEnvelope) Reset() {
+	*x = InventoryEventEnvelope{}
+	mi := &file_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryEventEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryEventEnvelope) ProtoMessage() {}
+
+func (x *InventoryEventEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryEventEnvelope.ProtoReflect.Descriptor instead.
+func (*InventoryEventEnvelope) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *InventoryEventEnvelope) GetEvent() isInventoryEventEnvelope_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *InventoryEventEnvelope) GetReservationSucceeded() *InventoryReservationSucceeded {
+	if x != nil {
+		if x, ok := x.Event.(*InventoryEventEnvelope_ReservationSucceeded); ok {
+			return x.ReservationSucceeded
+		}
+	}
+	return nil
+}
+
+func (x *InventoryEventEnvelope) GetReservationFailed() *InventoryReservationFailed {
+	if x != nil {
+		if x, ok := x.Event.(*InventoryEventEnvelope_ReservationFailed); ok {
+			return x.ReservationFailed
+		}
+	}
+	return nil
+}
+
+func (x *InventoryEventEnvelope) GetReservationReleased() *InventoryReservationReleased {
+	if x != nil {
+		if x, ok := x.Event.(*InventoryEventEnvelope_ReservationReleased); ok {
+			return x.ReservationReleased
+		}
+	}
+	return nil
+}
+
+func (x *InventoryEventEnvelope) GetProductCreated() *ProductCreated {
+	if x != nil {
+		if x, ok := x.Event.(*InventoryEventEnvelope_ProductCreated); ok {
+			return x.ProductCreated
+		}
+	}
+	return nil
+}
+
+func (x *InventoryEventEnvelope) GetProductUpdated() *ProductUpdated {
+	if x != nil {
+		if x, ok := x.Event.(*InventoryEventEnvelope_ProductUpdated); ok {
+			return x.ProductUpdated
+		}
+	}
+	return nil
+}
+
+func (x *InventoryEventEnvelope) GetPriceChanged() *PriceChanged {
+	if x != nil {
+		if x, ok := x.Event.(*InventoryEventEnvelope_PriceChanged); ok {
+			return x.PriceChanged
+		}
+	}
+	return nil
+}
+
+func (x *InventoryEventEnvelope) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+type isInventoryEventEnvelope_Event interface {
+	isInventoryEventEnvelope_Event()
+}
+
+type InventoryEventEnvelope_ReservationSucceeded struct {
+	ReservationSucceeded *InventoryReservationSucceeded `protobuf:"bytes,1,opt,name=reservation_succeeded,json=reservationSucceeded,proto3,oneof"`
+}
+
+type InventoryEventEnvelope_ReservationFailed struct {
+	ReservationFailed *InventoryReservationFailed `protobuf:"bytes,2,opt,name=reservation_failed,json=reservationFailed,proto3,oneof"`
+}
+
+type InventoryEventEnvelope_ReservationReleased struct {
+	ReservationReleased *InventoryReservationReleased `protobuf:"bytes,3,opt,name=reservation_released,json=reservationReleased,proto3,oneof"`
+}
+
+type InventoryEventEnvelope_ProductCreated struct {
+	ProductCreated *ProductCreated `protobuf:"bytes,4,opt,name=product_created,json=productCreated,proto3,oneof"`
+}
+
+type InventoryEventEnvelope_ProductUpdated struct {
+	ProductUpdated *ProductUpdated `protobuf:"bytes,5,opt,name=product_updated,json=productUpdated,proto3,oneof"`
+}
+
+type InventoryEventEnvelope_PriceChanged struct {
+	PriceChanged *PriceChanged `protobuf:"bytes,6,opt,name=price_changed,json=priceChanged,proto3,oneof"`
+}
+
+func (*InventoryEventEnvelope_ReservationSucceeded) isInventoryEventEnvelope_Event() {}
+
+func (*InventoryEventEnvelope_ReservationFailed) isInventoryEventEnvelope_Event() {}
+
+func (*InventoryEventEnvelope_ReservationReleased) isInventoryEventEnvelope_Event() {}
+
+func (*InventoryEventEnvelope_ProductCreated) isInventoryEventEnvelope_Event() {}
+
+func (*InventoryEventEnvelope_ProductUpdated) isInventoryEventEnvelope_Event() {}
+
+func (*InventoryEventEnvelope_PriceChanged) isInventoryEventEnvelope_Event() {}
+
+type InventoryReservationSucceeded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryReservationSucceeded) Reset() {
+	*x = InventoryReservationSucceeded{}
+	mi := &file_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryReservationSucceeded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryReservationSucceeded) ProtoMessage() {}
+
+func (x *InventoryReservationSucceeded) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryReservationSucceeded.ProtoReflect.Descriptor instead.
+func (*InventoryReservationSucceeded) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *InventoryReservationSucceeded) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// The order could not be reserved in full and nothing of it was; unavailable
+// lists the lines that were short.
+type InventoryReservationFailed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Unavailable   []*UnavailableItem     `protobuf:"bytes,3,rep,name=unavailable,proto3" json:"unavailable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryReservationFailed) Reset() {
+	*x = InventoryReservationFailed{}
+	mi := &file_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryReservationFailed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryReservationFailed) ProtoMessage() {}
+
+func (x *InventoryReservationFailed) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryReservationFailed.ProtoReflect.Descriptor instead.
+func (*InventoryReservationFailed) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *InventoryReservationFailed) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InventoryReservationFailed) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *InventoryReservationFailed) GetUnavailable() []*UnavailableItem {
+	if x != nil {
+		return x.Unavailable
+	}
+	return nil
+}
+
+// A SKU of a failed reservation: requested units against what was available.
+// An unknown SKU has nothing available.
+type UnavailableItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Requested     int32                  `protobuf:"varint,2,opt,name=requested,proto3" json:"requested,omitempty"`
+	Available     int32                  `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnavailableItem) Reset() {
+	*x = UnavailableItem{}
+	mi := &file_events_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnavailableItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnavailableItem) ProtoMessage() {}
+
+func (x *UnavailableItem) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnavailableItem.ProtoReflect.Descriptor instead.
+func (*UnavailableItem) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnavailableItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *UnavailableItem) GetRequested() int32 {
+	if x != nil {
+		return x.Requested
+	}
+	return 0
+}
+
+func (x *UnavailableItem) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+type InventoryReservationReleased struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryReservationReleased) Reset() {
+	*x = InventoryReservationReleased{}
+	mi := &file_events_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryReservationReleased) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryReservationReleased) ProtoMessage() {}
+
+func (x *InventoryReservationReleased) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryReservationReleased.ProtoReflect.Descriptor instead.
+func (*InventoryReservationReleased) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *InventoryReservationReleased) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// A product of the catalog as it is after a change; times are RFC 3339 and
+// on_hand is the stock when the change was made.
+type CatalogProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	OnHand        int32                  `protobuf:"varint,5,opt,name=on_hand,json=onHand,proto3" json:"on_hand,omitempty"`
+	Archived      bool                   `protobuf:"varint,6,opt,name=archived,proto3" json:"archived,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedBy     string                 `protobuf:"bytes,9,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CatalogProduct) Reset() {
+	*x = CatalogProduct{}
+	mi := &file_events_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CatalogProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CatalogProduct) ProtoMessage() {}
+
+func (x *CatalogProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CatalogProduct.ProtoReflect.Descriptor instead.
+func (*CatalogProduct) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CatalogProduct) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *CatalogProduct) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CatalogProduct) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CatalogProduct) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CatalogProduct) GetOnHand() int32 {
+	if x != nil {
+		return x.OnHand
+	}
+	return 0
+}
+
+func (x *CatalogProduct) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *CatalogProduct) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *CatalogProduct) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *CatalogProduct) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *CatalogProduct) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// An admin added a product to the catalog.
+type ProductCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *CatalogProduct        `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductCreated) Reset() {
+	*x = ProductCreated{}
+	mi := &file_events_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductCreated) ProtoMessage() {}
+
+func (x *ProductCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductCreated.ProtoReflect.Descriptor instead.
+func (*ProductCreated) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProductCreated) GetProduct() *CatalogProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// An admin renamed, restocked, repriced or archived a product. A new price
+// also comes as PriceChanged.
+type ProductUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *CatalogProduct        `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductUpdated) Reset() {
+	*x = ProductUpdated{}
+	mi := &file_events_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductUpdated) ProtoMessage() {}
+
+func (x *ProductUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductUpdated.ProtoReflect.Descriptor instead.
+func (*ProductUpdated) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ProductUpdated) GetProduct() *CatalogProduct {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type PriceChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	OldPrice      float64                `protobuf:"fixed64,2,opt,name=old_price,json=oldPrice,proto3" json:"old_price,omitempty"`
+	NewPrice      float64                `protobuf:"fixed64,3,opt,name=new_price,json=newPrice,proto3" json:"new_price,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	ChangedBy     string                 `protobuf:"bytes,5,opt,name=changed_by,json=changedBy,proto3" json:"changed_by,omitempty"`
+	ChangedAt     string                 `protobuf:"bytes,6,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceChanged) Reset() {
+	*x = PriceChanged{}
+	mi := &file_events_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceChanged) ProtoMessage() {}
+
+func (x *PriceChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceChanged.ProtoReflect.Descriptor instead.
+func (*PriceChanged) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PriceChanged) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *PriceChanged) GetOldPrice() float64 {
+	if x != nil {
+		return x.OldPrice
+	}
+	return 0
+}
+
+func (x *PriceChanged) GetNewPrice() float64 {
+	if x != nil {
+		return x.NewPrice
+	}
+	return 0
+}
+
+func (x *PriceChanged) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PriceChanged) GetChangedBy() string {
+	if x != nil {
+		return x.ChangedBy
+	}
+	return ""
+}
+
+func (x *PriceChanged) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+type PaymentEventEnvelope struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*PaymentEventEnvelope_PaymentSucceeded
+	//	*PaymentEventEnvelope_PaymentFailed
+	//	*PaymentEventEnvelope_PaymentRefunded
+	//	*PaymentEventEnvelope_RefundSucceeded
+	//	*PaymentEventEnvelope_RefundFailed
+	Event isPaymentEventEnvelope_Event `protobuf_oneof:"event"`
+	// event_id identifies the event across redeliveries, consumers use it to
+	// skip events they already processed
+	EventId       string `protobuf:"bytes,15,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentEventEnvelope) Reset() {
+	*x = PaymentEventEnvelope{}
+	mi := &file_events_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentEventEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentEventEnvelope) ProtoMessage() {}
+
+func (x *PaymentEventEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentEventEnvelope.ProtoReflect.Descriptor instead.
+func (*PaymentEventEnvelope) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PaymentEventEnvelope) GetEvent() isPaymentEventEnvelope_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *PaymentEventEnvelope) GetPaymentSucceeded() *PaymentSucceeded {
+	if x != nil {
+		if x, ok := x.Event.(*PaymentEventEnvelope_PaymentSucceeded); ok {
+			return x.PaymentSucceeded
+		}
+	}
+	return nil
+}
+
+func (x *PaymentEventEnvelope) GetPaymentFailed() *PaymentFailed {
+	if x != nil {
+		if x, ok := x.Event.(*PaymentEventEnvelope_PaymentFailed); ok {
+			return x.PaymentFailed
+		}
+	}
+	return nil
+}
+
+func (x *PaymentEventEnvelope) GetPaymentRefunded() *PaymentRefunded {
+	if x != nil {
+		if x, ok := x.Event.(*PaymentEventEnvelope_PaymentRefunded); ok {
+			return x.PaymentRefunded
+		}
+	}
+	return nil
+}
+
+func (x *PaymentEventEnvelope) GetRefundSucceeded() *RefundSucceeded {
+	if x != nil {
+		if x, ok := x.Event.(*PaymentEventEnvelope_RefundSucceeded); ok {
+			return x.RefundSucceeded
+		}
+	}
+	return nil
+}
+
+func (x *PaymentEventEnvelope) GetRefundFailed() *RefundFailed {
+	if x != nil {
+		if x, ok := x.Event.(*PaymentEventEnvelope_RefundFailed); ok {
+			return x.RefundFailed
+		}
+	}
+	return nil
+}
+
+func (x *PaymentEventEnvelope) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+type isPaymentEventEnvelope_Event interface {
+	isPaymentEventEnvelope_Event()
+}
+
+type PaymentEventEnvelope_PaymentSucceeded struct {
+	PaymentSucceeded *PaymentSucceeded `protobuf:"bytes,1,opt,name=payment_succeeded,json=paymentSucceeded,proto3,oneof"`
+}
+
+type PaymentEventEnvelope_PaymentFailed struct {
+	PaymentFailed *PaymentFailed `protobuf:"bytes,2,opt,name=payment_failed,json=paymentFailed,proto3,oneof"`
+}
+
+type PaymentEventEnvelope_PaymentRefunded struct {
+	PaymentRefunded *PaymentRefunded `protobuf:"bytes,3,opt,name=payment_refunded,json=paymentRefunded,proto3,oneof"`
+}
+
+type PaymentEventEnvelope_RefundSucceeded struct {
+	RefundSucceeded *RefundSucceeded `protobuf:"bytes,4,opt,name=refund_succeeded,json=refundSucceeded,proto3,oneof"`
+}
+
+type PaymentEventEnvelope_RefundFailed struct {
+	RefundFailed *RefundFailed `protobuf:"bytes,5,opt,name=refund_failed,json=refundFailed,proto3,oneof"`
+}
+
+func (*PaymentEventEnvelope_PaymentSucceeded) isPaymentEventEnvelope_Event() {}
+
+func (*PaymentEventEnvelope_PaymentFailed) isPaymentEventEnvelope_Event() {}
+
+func (*PaymentEventEnvelope_PaymentRefunded) isPaymentEventEnvelope_Event() {}
+
+func (*PaymentEventEnvelope_RefundSucceeded) isPaymentEventEnvelope_Event() {}
+
+func (*PaymentEventEnvelope_RefundFailed) isPaymentEventEnvelope_Event() {}
+
+type PaymentSucceeded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentSucceeded) Reset() {
+	*x = PaymentSucceeded{}
+	mi := &file_events_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentSucceeded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentSucceeded) ProtoMessage() {}
+
+func (x *PaymentSucceeded) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentSucceeded.ProtoReflect.Descriptor instead.
+func (*PaymentSucceeded) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PaymentSucceeded) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PaymentFailed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentFailed) Reset() {
+	*x = PaymentFailed{}
+	mi := &file_events_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentFailed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentFailed) ProtoMessage() {}
+
+func (x *PaymentFailed) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentFailed.ProtoReflect.Descriptor instead.
+func (*PaymentFailed) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PaymentFailed) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PaymentRefunded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentRefunded) Reset() {
+	*x = PaymentRefunded{}
+	mi := &file_events_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentRefunded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentRefunded) ProtoMessage() {}
+
+func (x *PaymentRefunded) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentRefunded.ProtoReflect.Descriptor instead.
+func (*PaymentRefunded) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PaymentRefunded) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Replies to RefundRequestedEvent; id is the order id
+type RefundSucceeded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RefundId      string                 `protobuf:"bytes,2,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	AmountMinor   int64                  `protobuf:"varint,5,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundSucceeded) Reset() {
+	*x = RefundSucceeded{}
+	mi := &file_events_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundSucceeded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundSucceeded) ProtoMessage() {}
+
+func (x *RefundSucceeded) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundSucceeded.ProtoReflect.Descriptor instead.
+func (*RefundSucceeded) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *OrderEventEnvelope) GetEvent() isOrderEventEnvelope_Event {
+func (x *RefundSucceeded) GetId() string {
 	if x != nil {
-		return x.Event
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *OrderEventEnvelope) GetOrderCreated() *OrderCreatedEvent {
+func (x *RefundSucceeded) GetRefundId() string {
 	if x != nil {
-		if x, ok := x.Event.(*OrderEventEnvelope_OrderCreated); ok {
-			return x.OrderCreated
-		}
+		return x.RefundId
 	}
-	return nil
+	return ""
 }
 
-type isOrderEventEnvelope_Event interface {
-	isOrderEventEnvelope_Event()
+func (x *RefundSucceeded) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
 }
 
-type OrderEventEnvelope_OrderCreated struct {
-	OrderCreated *OrderCreatedEvent `protobuf:"bytes,1,opt,name=order_created,json=orderCreated,proto3,oneof"`
+func (x *RefundSucceeded) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
 }
 
-func (*OrderEventEnvelope_OrderCreated) isOrderEventEnvelope_Event() {}
-
-type OrderCreatedEvent struct {
+type RefundFailed struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Items         []*Item                `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	RefundId      string                 `protobuf:"bytes,2,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderCreatedEvent) Reset() {
-	*x = OrderCreatedEvent{}
-	mi := &file_events_proto_msgTypes[2]
+func (x *RefundFailed) Reset() {
+	*x = RefundFailed{}
+	mi := &file_events_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderCreatedEvent) String() string {
+func (x *RefundFailed) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderCreatedEvent) ProtoMessage() {}
+func (*RefundFailed) ProtoMessage() {}
 
-func (x *OrderCreatedEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[2]
+func (x *RefundFailed) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -164,51 +1711,62 @@ func (x *OrderCreatedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderCreatedEvent.ProtoReflect.Descriptor instead.
-func (*OrderCreatedEvent) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use RefundFailed.ProtoReflect.Descriptor instead.
+func (*RefundFailed) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *OrderCreatedEvent) GetId() string {
+func (x *RefundFailed) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *OrderCreatedEvent) GetItems() []*Item {
+func (x *RefundFailed) GetRefundId() string {
 	if x != nil {
-		return x.Items
+		return x.RefundId
 	}
-	return nil
+	return ""
 }
 
-type InventoryEventEnvelope struct {
+func (x *RefundFailed) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ShippingEventEnvelope struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Event:
 	//
-	//	*InventoryEventEnvelope_ReservationSucceeded
-	//	*InventoryEventEnvelope_ReservationFailed
-	Event         isInventoryEventEnvelope_Event `protobuf_oneof:"event"`
+	//	*ShippingEventEnvelope_ShipmentCreated
+	//	*ShippingEventEnvelope_ShipmentDispatched
+	//	*ShippingEventEnvelope_ShipmentDelivered
+	Event isShippingEventEnvelope_Event `protobuf_oneof:"event"`
+	// event_id identifies the event across redeliveries, consumers use it to
+	// skip events they already processed
+	EventId       string `protobuf:"bytes,15,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InventoryEventEnvelope) Reset() {
-	*x = InventoryEventEnvelope{}
-	mi := &file_events_proto_msgTypes[3]
+func (x *ShippingEventEnvelope) Reset() {
+	*x = ShippingEventEnvelope{}
+	mi := &file_events_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InventoryEventEnvelope) String() string {
+func (x *ShippingEventEnvelope) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InventoryEventEnvelope) ProtoMessage() {}
+func (*ShippingEventEnvelope) ProtoMessage() {}
 
-func (x *InventoryEventEnvelope) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[3]
+func (x *ShippingEventEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -219,74 +1777,102 @@ func (x *InventoryEventEnvelope) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InventoryEventEnvelope.ProtoReflect.Descriptor instead.
-func (*InventoryEventEnvelope) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ShippingEventEnvelope.ProtoReflect.Descriptor instead.
+func (*ShippingEventEnvelope) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *InventoryEventEnvelope) GetEvent() isInventoryEventEnvelope_Event {
+func (x *ShippingEventEnvelope) GetEvent() isShippingEventEnvelope_Event {
 	if x != nil {
 		return x.Event
 	}
 	return nil
 }
 
-func (x *InventoryEventEnvelope) GetReservationSucceeded() *InventoryReservationSucceeded {
+func (x *ShippingEventEnvelope) GetShipmentCreated() *ShipmentCreated {
 	if x != nil {
-		if x, ok := x.Event.(*InventoryEventEnvelope_ReservationSucceeded); ok {
-			return x.ReservationSucceeded
+		if x, ok := x.Event.(*ShippingEventEnvelope_ShipmentCreated); ok {
+			return x.ShipmentCreated
 		}
 	}
 	return nil
 }
 
-func (x *InventoryEventEnvelope) GetReservationFailed() *InventoryReservationFailed {
+func (x *ShippingEventEnvelope) GetShipmentDispatched() *ShipmentDispatched {
 	if x != nil {
-		if x, ok := x.Event.(*InventoryEventEnvelope_ReservationFailed); ok {
-			return x.ReservationFailed
+		if x, ok := x.Event.(*ShippingEventEnvelope_ShipmentDispatched); ok {
+			return x.ShipmentDispatched
 		}
 	}
 	return nil
 }
 
-type isInventoryEventEnvelope_Event interface {
-	isInventoryEventEnvelope_Event()
+func (x *ShippingEventEnvelope) GetShipmentDelivered() *ShipmentDelivered {
+	if x != nil {
+		if x, ok := x.Event.(*ShippingEventEnvelope_ShipmentDelivered); ok {
+			return x.ShipmentDelivered
+		}
+	}
+	return nil
 }
 
-type InventoryEventEnvelope_ReservationSucceeded struct {
-	ReservationSucceeded *InventoryReservationSucceeded `protobuf:"bytes,1,opt,name=reservation_succeeded,json=reservationSucceeded,proto3,oneof"`
+func (x *ShippingEventEnvelope) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
 }
 
-type InventoryEventEnvelope_ReservationFailed struct {
-	ReservationFailed *InventoryReservationFailed `protobuf:"bytes,2,opt,name=reservation_failed,json=reservationFailed,proto3,oneof"`
+type isShippingEventEnvelope_Event interface {
+	isShippingEventEnvelope_Event()
 }
 
-func (*InventoryEventEnvelope_ReservationSucceeded) isInventoryEventEnvelope_Event() {}
+type ShippingEventEnvelope_ShipmentCreated struct {
+	ShipmentCreated *ShipmentCreated `protobuf:"bytes,1,opt,name=shipment_created,json=shipmentCreated,proto3,oneof"`
+}
 
-func (*InventoryEventEnvelope_ReservationFailed) isInventoryEventEnvelope_Event() {}
+type ShippingEventEnvelope_ShipmentDispatched struct {
+	ShipmentDispatched *ShipmentDispatched `protobuf:"bytes,2,opt,name=shipment_dispatched,json=shipmentDispatched,proto3,oneof"`
+}
 
-type InventoryReservationSucceeded struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ShippingEventEnvelope_ShipmentDelivered struct {
+	ShipmentDelivered *ShipmentDelivered `protobuf:"bytes,3,opt,name=shipment_delivered,json=shipmentDelivered,proto3,oneof"`
 }
 
-func (x *InventoryReservationSucceeded) Reset() {
-	*x = InventoryReservationSucceeded{}
-	mi := &file_events_proto_msgTypes[4]
+func (*ShippingEventEnvelope_ShipmentCreated) isShippingEventEnvelope_Event() {}
+
+func (*ShippingEventEnvelope_ShipmentDispatched) isShippingEventEnvelope_Event() {}
+
+func (*ShippingEventEnvelope_ShipmentDelivered) isShippingEventEnvelope_Event() {}
+
+// The carrier accepted the shipment of a paid order; id is the order id
+type ShipmentCreated struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShipmentId     string                 `protobuf:"bytes,2,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	Carrier        string                 `protobuf:"bytes,3,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,4,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	TrackingUrl    string                 `protobuf:"bytes,5,opt,name=tracking_url,json=trackingUrl,proto3" json:"tracking_url,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ShipmentCreated) Reset() {
+	*x = ShipmentCreated{}
+	mi := &file_events_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InventoryReservationSucceeded) String() string {
+func (x *ShipmentCreated) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InventoryReservationSucceeded) ProtoMessage() {}
+func (*ShipmentCreated) ProtoMessage() {}
 
-func (x *InventoryReservationSucceeded) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[4]
+func (x *ShipmentCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -297,88 +1883,79 @@ func (x *InventoryReservationSucceeded) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InventoryReservationSucceeded.ProtoReflect.Descriptor instead.
-func (*InventoryReservationSucceeded) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ShipmentCreated.ProtoReflect.Descriptor instead.
+func (*ShipmentCreated) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *InventoryReservationSucceeded) GetId() string {
+func (x *ShipmentCreated) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type InventoryReservationFailed struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *InventoryReservationFailed) Reset() {
-	*x = InventoryReservationFailed{}
-	mi := &file_events_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ShipmentCreated) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
 }
 
-func (x *InventoryReservationFailed) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ShipmentCreated) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
 }
 
-func (*InventoryReservationFailed) ProtoMessage() {}
-
-func (x *InventoryReservationFailed) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[5]
+func (x *ShipmentCreated) GetTrackingNumber() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.TrackingNumber
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use InventoryReservationFailed.ProtoReflect.Descriptor instead.
-func (*InventoryReservationFailed) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{5}
+func (x *ShipmentCreated) GetTrackingUrl() string {
+	if x != nil {
+		return x.TrackingUrl
+	}
+	return ""
 }
 
-func (x *InventoryReservationFailed) GetId() string {
+func (x *ShipmentCreated) GetCreatedAt() string {
 	if x != nil {
-		return x.Id
+		return x.CreatedAt
 	}
 	return ""
 }
 
-type PaymentEventEnvelope struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Event:
-	//
-	//	*PaymentEventEnvelope_PaymentSucceeded
-	//	*PaymentEventEnvelope_PaymentFailed
-	Event         isPaymentEventEnvelope_Event `protobuf_oneof:"event"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ShipmentDispatched struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShipmentId     string                 `protobuf:"bytes,2,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	Carrier        string                 `protobuf:"bytes,3,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,4,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	DispatchedAt   string                 `protobuf:"bytes,5,opt,name=dispatched_at,json=dispatchedAt,proto3" json:"dispatched_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *PaymentEventEnvelope) Reset() {
-	*x = PaymentEventEnvelope{}
-	mi := &file_events_proto_msgTypes[6]
+func (x *ShipmentDispatched) Reset() {
+	*x = ShipmentDispatched{}
+	mi := &file_events_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PaymentEventEnvelope) String() string {
+func (x *ShipmentDispatched) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PaymentEventEnvelope) ProtoMessage() {}
+func (*ShipmentDispatched) ProtoMessage() {}
 
-func (x *PaymentEventEnvelope) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[6]
+func (x *ShipmentDispatched) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -389,74 +1966,72 @@ func (x *PaymentEventEnvelope) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PaymentEventEnvelope.ProtoReflect.Descriptor instead.
-func (*PaymentEventEnvelope) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ShipmentDispatched.ProtoReflect.Descriptor instead.
+func (*ShipmentDispatched) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *PaymentEventEnvelope) GetEvent() isPaymentEventEnvelope_Event {
+func (x *ShipmentDispatched) GetId() string {
 	if x != nil {
-		return x.Event
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *PaymentEventEnvelope) GetPaymentSucceeded() *PaymentSucceeded {
+func (x *ShipmentDispatched) GetShipmentId() string {
 	if x != nil {
-		if x, ok := x.Event.(*PaymentEventEnvelope_PaymentSucceeded); ok {
-			return x.PaymentSucceeded
-		}
+		return x.ShipmentId
 	}
-	return nil
+	return ""
 }
 
-func (x *PaymentEventEnvelope) GetPaymentFailed() *PaymentFailed {
+func (x *ShipmentDispatched) GetCarrier() string {
 	if x != nil {
-		if x, ok := x.Event.(*PaymentEventEnvelope_PaymentFailed); ok {
-			return x.PaymentFailed
-		}
+		return x.Carrier
 	}
-	return nil
-}
-
-type isPaymentEventEnvelope_Event interface {
-	isPaymentEventEnvelope_Event()
+	return ""
 }
 
-type PaymentEventEnvelope_PaymentSucceeded struct {
-	PaymentSucceeded *PaymentSucceeded `protobuf:"bytes,1,opt,name=payment_succeeded,json=paymentSucceeded,proto3,oneof"`
+func (x *ShipmentDispatched) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
 }
 
-type PaymentEventEnvelope_PaymentFailed struct {
-	PaymentFailed *PaymentFailed `protobuf:"bytes,2,opt,name=payment_failed,json=paymentFailed,proto3,oneof"`
+func (x *ShipmentDispatched) GetDispatchedAt() string {
+	if x != nil {
+		return x.DispatchedAt
+	}
+	return ""
 }
 
-func (*PaymentEventEnvelope_PaymentSucceeded) isPaymentEventEnvelope_Event() {}
-
-func (*PaymentEventEnvelope_PaymentFailed) isPaymentEventEnvelope_Event() {}
-
-type PaymentSucceeded struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ShipmentDelivered struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShipmentId     string                 `protobuf:"bytes,2,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	Carrier        string                 `protobuf:"bytes,3,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,4,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	DeliveredAt    string                 `protobuf:"bytes,5,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *PaymentSucceeded) Reset() {
-	*x = PaymentSucceeded{}
-	mi := &file_events_proto_msgTypes[7]
+func (x *ShipmentDelivered) Reset() {
+	*x = ShipmentDelivered{}
+	mi := &file_events_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PaymentSucceeded) String() string {
+func (x *ShipmentDelivered) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PaymentSucceeded) ProtoMessage() {}
+func (*ShipmentDelivered) ProtoMessage() {}
 
-func (x *PaymentSucceeded) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[7]
+func (x *ShipmentDelivered) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -467,58 +2042,42 @@ func (x *PaymentSucceeded) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PaymentSucceeded.ProtoReflect.Descriptor instead.
-func (*PaymentSucceeded) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ShipmentDelivered.ProtoReflect.Descriptor instead.
+func (*ShipmentDelivered) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *PaymentSucceeded) GetId() string {
+func (x *ShipmentDelivered) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type PaymentFailed struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *PaymentFailed) Reset() {
-	*x = PaymentFailed{}
-	mi := &file_events_proto_msgTypes[8]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *PaymentFailed) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ShipmentDelivered) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
 }
 
-func (*PaymentFailed) ProtoMessage() {}
-
-func (x *PaymentFailed) ProtoReflect() protoreflect.Message {
-	mi := &file_events_proto_msgTypes[8]
+func (x *ShipmentDelivered) GetCarrier() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Carrier
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use PaymentFailed.ProtoReflect.Descriptor instead.
-func (*PaymentFailed) Descriptor() ([]byte, []int) {
-	return file_events_proto_rawDescGZIP(), []int{8}
+func (x *ShipmentDelivered) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
 }
 
-func (x *PaymentFailed) GetId() string {
+func (x *ShipmentDelivered) GetDeliveredAt() string {
 	if x != nil {
-		return x.Id
+		return x.DeliveredAt
 	}
 	return ""
 }
@@ -527,31 +2086,160 @@ var File_events_proto protoreflect.FileDescriptor
 
 const file_events_proto_rawDesc = "" +
 	"\n" +
-	"\fevents.proto\x12\x06events\"\x16\n" +
+	"\fevents.proto\x12\x06events\"~\n" +
 	"\x04Item\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"_\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12(\n" +
+	"\x10unit_price_minor\x18\x05 \x01(\x03R\x0eunitPriceMinor\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrencyJ\x04\b\x03\x10\x04\"\xb0\x01\n" +
+	"\aAddress\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05line1\x18\x02 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x03 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\"\x94\x03\n" +
 	"\x12OrderEventEnvelope\x12@\n" +
-	"\rorder_created\x18\x01 \x01(\v2\x19.events.OrderCreatedEventH\x00R\forderCreatedB\a\n" +
-	"\x05event\"G\n" +
+	"\rorder_created\x18\x01 \x01(\v2\x19.events.OrderCreatedEventH\x00R\forderCreated\x12@\n" +
+	"\rorder_expired\x18\x02 \x01(\v2\x19.events.OrderExpiredEventH\x00R\forderExpired\x12F\n" +
+	"\x0forder_cancelled\x18\x03 \x01(\v2\x1b.events.OrderCancelledEventH\x00R\x0eorderCancelled\x12I\n" +
+	"\x10refund_requested\x18\x04 \x01(\v2\x1c.events.RefundRequestedEventH\x00R\x0frefundRequested\x12C\n" +
+	"\x0eorder_refunded\x18\x05 \x01(\v2\x1a.events.OrderRefundedEventH\x00R\rorderRefunded\x12\x19\n" +
+	"\bevent_id\x18\x0f \x01(\tR\aeventIdB\a\n" +
+	"\x05event\"\xaa\x02\n" +
 	"\x11OrderCreatedEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
-	"\x05items\x18\x02 \x03(\v2\f.events.ItemR\x05items\"\xd4\x01\n" +
+	"\x05items\x18\x02 \x03(\v2\f.events.ItemR\x05items\x12%\n" +
+	"\x0esubtotal_minor\x18\t \x01(\x03R\rsubtotalMinor\x12\x1f\n" +
+	"\vtotal_minor\x18\n" +
+	" \x01(\x03R\n" +
+	"totalMinor\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x1f\n" +
+	"\vcustomer_id\x18\x06 \x01(\tR\n" +
+	"customerId\x12\x14\n" +
+	"\x05email\x18\a \x01(\tR\x05email\x12:\n" +
+	"\x10shipping_address\x18\b \x01(\v2\x0f.events.AddressR\x0fshippingAddressJ\x04\b\x03\x10\x04J\x04\b\x04\x10\x05\"#\n" +
+	"\x11OrderExpiredEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"%\n" +
+	"\x13OrderCancelledEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xd8\x01\n" +
+	"\x14RefundRequestedEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\trefund_id\x18\x02 \x01(\tR\brefundId\x12\"\n" +
+	"\x05items\x18\x03 \x03(\v2\f.events.ItemR\x05items\x12!\n" +
+	"\famount_minor\x18\b \x01(\x03R\vamountMinor\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04full\x18\x06 \x01(\bR\x04full\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reasonJ\x04\b\x04\x10\x05\"y\n" +
+	"\x12OrderRefundedEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\trefund_id\x18\x02 \x01(\tR\brefundId\x12\"\n" +
+	"\x05items\x18\x03 \x03(\v2\f.events.ItemR\x05items\x12\x12\n" +
+	"\x04full\x18\x04 \x01(\bR\x04full\"\x8d\x04\n" +
 	"\x16InventoryEventEnvelope\x12\\\n" +
 	"\x15reservation_succeeded\x18\x01 \x01(\v2%.events.InventoryReservationSucceededH\x00R\x14reservationSucceeded\x12S\n" +
-	"\x12reservation_failed\x18\x02 \x01(\v2\".events.InventoryReservationFailedH\x00R\x11reservationFailedB\a\n" +
+	"\x12reservation_failed\x18\x02 \x01(\v2\".events.InventoryReservationFailedH\x00R\x11reservationFailed\x12Y\n" +
+	"\x14reservation_released\x18\x03 \x01(\v2$.events.InventoryReservationReleasedH\x00R\x13reservationReleased\x12A\n" +
+	"\x0fproduct_created\x18\x04 \x01(\v2\x16.events.ProductCreatedH\x00R\x0eproductCreated\x12A\n" +
+	"\x0fproduct_updated\x18\x05 \x01(\v2\x16.events.ProductUpdatedH\x00R\x0eproductUpdated\x12;\n" +
+	"\rprice_changed\x18\x06 \x01(\v2\x14.events.PriceChangedH\x00R\fpriceChanged\x12\x19\n" +
+	"\bevent_id\x18\x0f \x01(\tR\aeventIdB\a\n" +
 	"\x05event\"/\n" +
 	"\x1dInventoryReservationSucceeded\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\",\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x7f\n" +
 	"\x1aInventoryReservationFailed\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\xa8\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x129\n" +
+	"\vunavailable\x18\x03 \x03(\v2\x17.events.UnavailableItemR\vunavailable\"_\n" +
+	"\x0fUnavailableItem\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x1c\n" +
+	"\trequested\x18\x02 \x01(\x05R\trequested\x12\x1c\n" +
+	"\tavailable\x18\x03 \x01(\x05R\tavailable\".\n" +
+	"\x1cInventoryReservationReleased\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x99\x02\n" +
+	"\x0eCatalogProduct\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x17\n" +
+	"\aon_hand\x18\x05 \x01(\x05R\x06onHand\x12\x1a\n" +
+	"\barchived\x18\x06 \x01(\bR\barchived\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\a \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_by\x18\t \x01(\tR\tupdatedBy\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\"B\n" +
+	"\x0eProductCreated\x120\n" +
+	"\aproduct\x18\x01 \x01(\v2\x16.events.CatalogProductR\aproduct\"B\n" +
+	"\x0eProductUpdated\x120\n" +
+	"\aproduct\x18\x01 \x01(\v2\x16.events.CatalogProductR\aproduct\"\xb4\x01\n" +
+	"\fPriceChanged\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x1b\n" +
+	"\told_price\x18\x02 \x01(\x01R\boldPrice\x12\x1b\n" +
+	"\tnew_price\x18\x03 \x01(\x01R\bnewPrice\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"changed_by\x18\x05 \x01(\tR\tchangedBy\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x06 \x01(\tR\tchangedAt\"\x8c\x03\n" +
 	"\x14PaymentEventEnvelope\x12G\n" +
 	"\x11payment_succeeded\x18\x01 \x01(\v2\x18.events.PaymentSucceededH\x00R\x10paymentSucceeded\x12>\n" +
-	"\x0epayment_failed\x18\x02 \x01(\v2\x15.events.PaymentFailedH\x00R\rpaymentFailedB\a\n" +
+	"\x0epayment_failed\x18\x02 \x01(\v2\x15.events.PaymentFailedH\x00R\rpaymentFailed\x12D\n" +
+	"\x10payment_refunded\x18\x03 \x01(\v2\x17.events.PaymentRefundedH\x00R\x0fpaymentRefunded\x12D\n" +
+	"\x10refund_succeeded\x18\x04 \x01(\v2\x17.events.RefundSucceededH\x00R\x0frefundSucceeded\x12;\n" +
+	"\rrefund_failed\x18\x05 \x01(\v2\x14.events.RefundFailedH\x00R\frefundFailed\x12\x19\n" +
+	"\bevent_id\x18\x0f \x01(\tR\aeventIdB\a\n" +
 	"\x05event\"\"\n" +
 	"\x10PaymentSucceeded\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\x1f\n" +
 	"\rPaymentFailed\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02idB\x90\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"!\n" +
+	"\x0fPaymentRefunded\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x83\x01\n" +
+	"\x0fRefundSucceeded\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\trefund_id\x18\x02 \x01(\tR\brefundId\x12!\n" +
+	"\famount_minor\x18\x05 \x01(\x03R\vamountMinor\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrencyJ\x04\b\x03\x10\x04\"S\n" +
+	"\fRefundFailed\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\trefund_id\x18\x02 \x01(\tR\brefundId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x9c\x02\n" +
+	"\x15ShippingEventEnvelope\x12D\n" +
+	"\x10shipment_created\x18\x01 \x01(\v2\x17.events.ShipmentCreatedH\x00R\x0fshipmentCreated\x12M\n" +
+	"\x13shipment_dispatched\x18\x02 \x01(\v2\x1a.events.ShipmentDispatchedH\x00R\x12shipmentDispatched\x12J\n" +
+	"\x12shipment_delivered\x18\x03 \x01(\v2\x19.events.ShipmentDeliveredH\x00R\x11shipmentDelivered\x12\x19\n" +
+	"\bevent_id\x18\x0f \x01(\tR\aeventIdB\a\n" +
+	"\x05event\"\xc7\x01\n" +
+	"\x0fShipmentCreated\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vshipment_id\x18\x02 \x01(\tR\n" +
+	"shipmentId\x12\x18\n" +
+	"\acarrier\x18\x03 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x04 \x01(\tR\x0etrackingNumber\x12!\n" +
+	"\ftracking_url\x18\x05 \x01(\tR\vtrackingUrl\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\"\xad\x01\n" +
+	"\x12ShipmentDispatched\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vshipment_id\x18\x02 \x01(\tR\n" +
+	"shipmentId\x12\x18\n" +
+	"\acarrier\x18\x03 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x04 \x01(\tR\x0etrackingNumber\x12#\n" +
+	"\rdispatched_at\x18\x05 \x01(\tR\fdispatchedAt\"\xaa\x01\n" +
+	"\x11ShipmentDelivered\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vshipment_id\x18\x02 \x01(\tR\n" +
+	"shipmentId\x12\x18\n" +
+	"\acarrier\x18\x03 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x04 \x01(\tR\x0etrackingNumber\x12!\n" +
+	"\fdelivered_at\x18\x05 \x01(\tR\vdeliveredAtB\x90\x01\n" +
 	"\n" +
 	"com.eventsB\vEventsProtoP\x01Z=github.com/axmz/go-saga-microservices/pkg/proto/events;events\xa2\x02\x03EXX\xaa\x02\x06Events\xca\x02\x06Events\xe2\x02\x12Events\\GPBMetadata\xea\x02\x06Eventsb\x06proto3"
 
@@ -567,30 +2255,68 @@ func file_events_proto_rawDescGZIP() []byte {
 	return file_events_proto_rawDescData
 }
 
-var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_events_proto_goTypes = []any{
 	(*Item)(nil),                          // 0: events.Item
-	(*OrderEventEnvelope)(nil),            // 1: events.OrderEventEnvelope
-	(*OrderCreatedEvent)(nil),             // 2: events.OrderCreatedEvent
-	(*InventoryEventEnvelope)(nil),        // 3: events.InventoryEventEnvelope
-	(*InventoryReservationSucceeded)(nil), // 4: events.InventoryReservationSucceeded
-	(*InventoryReservationFailed)(nil),    // 5: events.InventoryReservationFailed
-	(*PaymentEventEnvelope)(nil),          // 6: events.PaymentEventEnvelope
-	(*PaymentSucceeded)(nil),              // 7: events.PaymentSucceeded
-	(*PaymentFailed)(nil),                 // 8: events.PaymentFailed
+	(*Address)(nil),                       // 1: events.Address
+	(*OrderEventEnvelope)(nil),            // 2: events.OrderEventEnvelope
+	(*OrderCreatedEvent)(nil),             // 3: events.OrderCreatedEvent
+	(*OrderExpiredEvent)(nil),             // 4: events.OrderExpiredEvent
+	(*OrderCancelledEvent)(nil),           // 5: events.OrderCancelledEvent
+	(*RefundRequestedEvent)(nil),          // 6: events.RefundRequestedEvent
+	(*OrderRefundedEvent)(nil),            // 7: events.OrderRefundedEvent
+	(*InventoryEventEnvelope)(nil),        // 8: events.InventoryEventEnvelope
+	(*InventoryReservationSucceeded)(nil), // 9: events.InventoryReservationSucceeded
+	(*InventoryReservationFailed)(nil),    // 10: events.InventoryReservationFailed
+	(*UnavailableItem)(nil),               // 11: events.UnavailableItem
+	(*InventoryReservationReleased)(nil),  // 12: events.InventoryReservationReleased
+	(*CatalogProduct)(nil),                // 13: events.CatalogProduct
+	(*ProductCreated)(nil),                // 14: events.ProductCreated
+	(*ProductUpdated)(nil),                // 15: events.ProductUpdated
+	(*PriceChanged)(nil),                  // 16: events.PriceChanged
+	(*PaymentEventEnvelope)(nil),          // 17: events.PaymentEventEnvelope
+	(*PaymentSucceeded)(nil),              // 18: events.PaymentSucceeded
+	(*PaymentFailed)(nil),                 // 19: events.PaymentFailed
+	(*PaymentRefunded)(nil),               // 20: events.PaymentRefunded
+	(*RefundSucceeded)(nil),               // 21: events.RefundSucceeded
+	(*RefundFailed)(nil),                  // 22: events.RefundFailed
+	(*ShippingEventEnvelope)(nil),         // 23: events.ShippingEventEnvelope
+	(*ShipmentCreated)(nil),               // 24: events.ShipmentCreated
+	(*ShipmentDispatched)(nil),            // 25: events.ShipmentDispatched
+	(*ShipmentDelivered)(nil),             // 26: events.ShipmentDelivered
 }
 var file_events_proto_depIdxs = []int32{
-	2, // 0: events.OrderEventEnvelope.order_created:type_name -> events.OrderCreatedEvent
-	0, // 1: events.OrderCreatedEvent.items:type_name -> events.Item
-	4, // 2: events.InventoryEventEnvelope.reservation_succeeded:type_name -> events.InventoryReservationSucceeded
-	5, // 3: events.InventoryEventEnvelope.reservation_failed:type_name -> events.InventoryReservationFailed
-	7, // 4: events.PaymentEventEnvelope.payment_succeeded:type_name -> events.PaymentSucceeded
-	8, // 5: events.PaymentEventEnvelope.payment_failed:type_name -> events.PaymentFailed
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	3,  // 0: events.OrderEventEnvelope.order_created:type_name -> events.OrderCreatedEvent
+	4,  // 1: events.OrderEventEnvelope.order_expired:type_name -> events.OrderExpiredEvent
+	5,  // 2: events.OrderEventEnvelope.order_cancelled:type_name -> events.OrderCancelledEvent
+	6,  // 3: events.OrderEventEnvelope.refund_requested:type_name -> events.RefundRequestedEvent
+	7,  // 4: events.OrderEventEnvelope.order_refunded:type_name -> events.OrderRefundedEvent
+	0,  // 5: events.OrderCreatedEvent.items:type_name -> events.Item
+	1,  // 6: events.OrderCreatedEvent.shipping_address:type_name -> events.Address
+	0,  // 7: events.RefundRequestedEvent.items:type_name -> events.Item
+	0,  // 8: events.OrderRefundedEvent.items:type_name -> events.Item
+	9,  // 9: events.InventoryEventEnvelope.reservation_succeeded:type_name -> events.InventoryReservationSucceeded
+	10, // 10: events.InventoryEventEnvelope.reservation_failed:type_name -> events.InventoryReservationFailed
+	12, // 11: events.InventoryEventEnvelope.reservation_released:type_name -> events.InventoryReservationReleased
+	14, // 12: events.InventoryEventEnvelope.product_created:type_name -> events.ProductCreated
+	15, // 13: events.InventoryEventEnvelope.product_updated:type_name -> events.ProductUpdated
+	16, // 14: events.InventoryEventEnvelope.price_changed:type_name -> events.PriceChanged
+	11, // 15: events.InventoryReservationFailed.unavailable:type_name -> events.UnavailableItem
+	13, // 16: events.ProductCreated.product:type_name -> events.CatalogProduct
+	13, // 17: events.ProductUpdated.product:type_name -> events.CatalogProduct
+	18, // 18: events.PaymentEventEnvelope.payment_succeeded:type_name -> events.PaymentSucceeded
+	19, // 19: events.PaymentEventEnvelope.payment_failed:type_name -> events.PaymentFailed
+	20, // 20: events.PaymentEventEnvelope.payment_refunded:type_name -> events.PaymentRefunded
+	21, // 21: events.PaymentEventEnvelope.refund_succeeded:type_name -> events.RefundSucceeded
+	22, // 22: events.PaymentEventEnvelope.refund_failed:type_name -> events.RefundFailed
+	24, // 23: events.ShippingEventEnvelope.shipment_created:type_name -> events.ShipmentCreated
+	25, // 24: events.ShippingEventEnvelope.shipment_dispatched:type_name -> events.ShipmentDispatched
+	26, // 25: events.ShippingEventEnvelope.shipment_delivered:type_name -> events.ShipmentDelivered
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_events_proto_init() }
@@ -598,16 +2324,32 @@ func file_events_proto_init() {
 	if File_events_proto != nil {
 		return
 	}
-	file_events_proto_msgTypes[1].OneofWrappers = []any{
+	file_events_proto_msgTypes[2].OneofWrappers = []any{
 		(*OrderEventEnvelope_OrderCreated)(nil),
+		(*OrderEventEnvelope_OrderExpired)(nil),
+		(*OrderEventEnvelope_OrderCancelled)(nil),
+		(*OrderEventEnvelope_RefundRequested)(nil),
+		(*OrderEventEnvelope_OrderRefunded)(nil),
 	}
-	file_events_proto_msgTypes[3].OneofWrappers = []any{
+	file_events_proto_msgTypes[8].OneofWrappers = []any{
 		(*InventoryEventEnvelope_ReservationSucceeded)(nil),
 		(*InventoryEventEnvelope_ReservationFailed)(nil),
+		(*InventoryEventEnvelope_ReservationReleased)(nil),
+		(*InventoryEventEnvelope_ProductCreated)(nil),
+		(*InventoryEventEnvelope_ProductUpdated)(nil),
+		(*InventoryEventEnvelope_PriceChanged)(nil),
 	}
-	file_events_proto_msgTypes[6].OneofWrappers = []any{
+	file_events_proto_msgTypes[17].OneofWrappers = []any{
 		(*PaymentEventEnvelope_PaymentSucceeded)(nil),
 		(*PaymentEventEnvelope_PaymentFailed)(nil),
+		(*PaymentEventEnvelope_PaymentRefunded)(nil),
+		(*PaymentEventEnvelope_RefundSucceeded)(nil),
+		(*PaymentEventEnvelope_RefundFailed)(nil),
+	}
+	file_events_proto_msgTypes[23].OneofWrappers = []any{
+		(*ShippingEventEnvelope_ShipmentCreated)(nil),
+		(*ShippingEventEnvelope_ShipmentDispatched)(nil),
+		(*ShippingEventEnvelope_ShipmentDelivered)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -615,7 +2357,7 @@ func file_events_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_proto_rawDesc), len(file_events_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,132 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: rpc.proto
+
+package rpc
+
+import (
+	http "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_rpc_proto protoreflect.FileDescriptor
+
+const file_rpc_proto_rawDesc = "" +
+	"\n" +
+	"\trpc.proto\x12\x03rpc\x1a\n" +
+	"http.proto2\xbf\x04\n" +
+	"\fOrderService\x12B\n" +
+	"\vCreateOrder\x12\x18.http.CreateOrderRequest\x1a\x19.http.CreateOrderResponse\x129\n" +
+	"\bGetOrder\x12\x15.http.GetOrderRequest\x1a\x16.http.GetOrderResponse\x12?\n" +
+	"\n" +
+	"ListOrders\x12\x17.http.ListOrdersRequest\x1a\x18.http.ListOrdersResponse\x12N\n" +
+	"\x0fGetOrderHistory\x12\x1c.http.GetOrderHistoryRequest\x1a\x1d.http.GetOrderHistoryResponse\x12B\n" +
+	"\vCancelOrder\x12\x18.http.CancelOrderRequest\x1a\x19.http.CancelOrderResponse\x12B\n" +
+	"\vRefundOrder\x12\x18.http.RefundOrderRequest\x1a\x19.http.RefundOrderResponse\x12B\n" +
+	"\vListRefunds\x12\x18.http.ListRefundsRequest\x1a\x19.http.ListRefundsResponse\x12S\n" +
+	"\x10WatchOrderStatus\x12\x1d.http.WatchOrderStatusRequest\x1a\x1e.http.WatchOrderStatusResponse0\x012\xde\x01\n" +
+	"\x10InventoryService\x12B\n" +
+	"\vGetProducts\x12\x18.http.GetProductsRequest\x1a\x19.http.GetProductsResponse\x12<\n" +
+	"\tGetPrices\x12\x16.http.GetPricesRequest\x1a\x17.http.GetPricesResponse\x12H\n" +
+	"\rResetProducts\x12\x1a.http.ResetProductsRequest\x1a\x1b.http.ResetProductsResponse2\xa1\x01\n" +
+	"\x0ePaymentService\x12K\n" +
+	"\x0ePaymentSuccess\x12\x1b.http.PaymentSuccessRequest\x1a\x1c.http.PaymentSuccessResponse\x12B\n" +
+	"\vPaymentFail\x12\x18.http.PaymentFailRequest\x1a\x19.http.PaymentFailResponseBx\n" +
+	"\acom.rpcB\bRpcProtoP\x01Z7github.com/axmz/go-saga-microservices/pkg/proto/rpc;rpc\xa2\x02\x03RXX\xaa\x02\x03Rpc\xca\x02\x03Rpc\xe2\x02\x0fRpc\\GPBMetadata\xea\x02\x03Rpcb\x06proto3"
+
+var file_rpc_proto_goTypes = []any{
+	(*http.CreateOrderRequest)(nil),       // 0: http.CreateOrderRequest
+	(*http.GetOrderRequest)(nil),          // 1: http.GetOrderRequest
+	(*http.ListOrdersRequest)(nil),        // 2: http.ListOrdersRequest
+	(*http.GetOrderHistoryRequest)(nil),   // 3: http.GetOrderHistoryRequest
+	(*http.CancelOrderRequest)(nil),       // 4: http.CancelOrderRequest
+	(*http.RefundOrderRequest)(nil),       // 5: http.RefundOrderRequest
+	(*http.ListRefundsRequest)(nil),       // 6: http.ListRefundsRequest
+	(*http.WatchOrderStatusRequest)(nil),  // 7: http.WatchOrderStatusRequest
+	(*http.GetProductsRequest)(nil),       // 8: http.GetProductsRequest
+	(*http.GetPricesRequest)(nil),         // 9: http.GetPricesRequest
+	(*http.ResetProductsRequest)(nil),     // 10: http.ResetProductsRequest
+	(*http.PaymentSuccessRequest)(nil),    // 11: http.PaymentSuccessRequest
+	(*http.PaymentFailRequest)(nil),       // 12: http.PaymentFailRequest
+	(*http.CreateOrderResponse)(nil),      // 13: http.CreateOrderResponse
+	(*http.GetOrderResponse)(nil),         // 14: http.GetOrderResponse
+	(*http.ListOrdersResponse)(nil),       // 15: http.ListOrdersResponse
+	(*http.GetOrderHistoryResponse)(nil),  // 16: http.GetOrderHistoryResponse
+	(*http.CancelOrderResponse)(nil),      // 17: http.CancelOrderResponse
+	(*http.RefundOrderResponse)(nil),      // 18: http.RefundOrderResponse
+	(*http.ListRefundsResponse)(nil),      // 19: http.ListRefundsResponse
+	(*http.WatchOrderStatusResponse)(nil), // 20: http.WatchOrderStatusResponse
+	(*http.GetProductsResponse)(nil),      // 21: http.GetProductsResponse
+	(*http.GetPricesResponse)(nil),        // 22: http.GetPricesResponse
+	(*http.ResetProductsResponse)(nil),    // 23: http.ResetProductsResponse
+	(*http.PaymentSuccessResponse)(nil),   // 24: http.PaymentSuccessResponse
+	(*http.PaymentFailResponse)(nil),      // 25: http.PaymentFailResponse
+}
+var file_rpc_proto_depIdxs = []int32{
+	0,  // 0: rpc.OrderService.CreateOrder:input_type -> http.CreateOrderRequest
+	1,  // 1: rpc.OrderService.GetOrder:input_type -> http.GetOrderRequest
+	2,  // 2: rpc.OrderService.ListOrders:input_type -> http.ListOrdersRequest
+	3,  // 3: rpc.OrderService.GetOrderHistory:input_type -> http.GetOrderHistoryRequest
+	4,  // 4: rpc.OrderService.CancelOrder:input_type -> http.CancelOrderRequest
+	5,  // 5: rpc.OrderService.RefundOrder:input_type -> http.RefundOrderRequest
+	6,  // 6: rpc.OrderService.ListRefunds:input_type -> http.ListRefundsRequest
+	7,  // 7: rpc.OrderService.WatchOrderStatus:input_type -> http.WatchOrderStatusRequest
+	8,  // 8: rpc.InventoryService.GetProducts:input_type -> http.GetProductsRequest
+	9,  // 9: rpc.InventoryService.GetPrices:input_type -> http.GetPricesRequest
+	10, // 10: rpc.InventoryService.ResetProducts:input_type -> http.ResetProductsRequest
+	11, // 11: rpc.PaymentService.PaymentSuccess:input_type -> http.PaymentSuccessRequest
+	12, // 12: rpc.PaymentService.PaymentFail:input_type -> http.PaymentFailRequest
+	13, // 13: rpc.OrderService.CreateOrder:output_type -> http.CreateOrderResponse
+	14, // 14: rpc.OrderService.GetOrder:output_type -> http.GetOrderResponse
+	15, // 15: rpc.OrderService.ListOrders:output_type -> http.ListOrdersResponse
+	16, // 16: rpc.OrderService.GetOrderHistory:output_type -> http.GetOrderHistoryResponse
+	17, // 17: rpc.OrderService.CancelOrder:output_type -> http.CancelOrderResponse
+	18, // 18: rpc.OrderService.RefundOrder:output_type -> http.RefundOrderResponse
+	19, // 19: rpc.OrderService.ListRefunds:output_type -> http.ListRefundsResponse
+	20, // 20: rpc.OrderService.WatchOrderStatus:output_type -> http.WatchOrderStatusResponse
+	21, // 21: rpc.InventoryService.GetProducts:output_type -> http.GetProductsResponse
+	22, // 22: rpc.InventoryService.GetPrices:output_type -> http.GetPricesResponse
+	23, // 23: rpc.InventoryService.ResetProducts:output_type -> http.ResetProductsResponse
+	24, // 24: rpc.PaymentService.PaymentSuccess:output_type -> http.PaymentSuccessResponse
+	25, // 25: rpc.PaymentService.PaymentFail:output_type -> http.PaymentFailResponse
+	13, // [13:26] is the sub-list for method output_type
+	0,  // [0:13] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_proto_init() }
+func file_rpc_proto_init() {
+	if File_rpc_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_proto_rawDesc), len(file_rpc_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_rpc_proto_goTypes,
+		DependencyIndexes: file_rpc_proto_depIdxs,
+	}.Build()
+	File_rpc_proto = out.File
+	file_rpc_proto_goTypes = nil
+	file_rpc_proto_depIdxs = nil
+}
@@ -1,54 +1,77 @@
 package app
 
 import (
+	"fmt"
 	"log/slog"
 
 	"github.com/axmz/go-saga-microservices/config"
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/consumer"
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/handler"
-	"github.com/axmz/go-saga-microservices/inventory-service/internal/publisher"
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/repository"
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/router"
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/service"
 	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/grpc"
 	"github.com/axmz/go-saga-microservices/lib/adapter/http"
 	"github.com/axmz/go-saga-microservices/lib/adapter/kafka"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
 )
 
 type App struct {
-	Config    *config.Config
-	Consumer  *consumer.Consumer
-	DB        *db.DB
-	HTTP      *http.Server
-	Kafka     *kafka.Broker
-	Log       *slog.Logger
-	Publisher *publisher.Publisher
-	Repo      *repository.Repository
-	Services  *service.Service
+	Cleaner  *db.OutboxCleaner
+	Config   *config.Config
+	Consumer *consumer.Consumer
+	DB       *db.DB
+	Debug    *http.Server
+	GRPC     *grpc.Server
+	HTTP     *http.Server
+	Kafka    *kafka.Broker
+	Log      *slog.Logger
+	Relay    *db.OutboxRelay
+	Repo     *repository.Repository
+	Services *service.Service
 }
 
 func SetupApp(
 	cfg *config.Config,
 	log *slog.Logger,
-	db *db.DB,
+	database *db.DB,
 	srv *http.Server,
+	dsrv *http.Server,
+	gsrv *grpc.Server,
 	kfk *kafka.Broker,
 ) (*App, error) {
-	rep := repository.New(db)
-	pub := publisher.New(kfk.Writer)
-	svc := service.New(rep, pub)
+	rep := repository.New(database)
+	svc := service.New(rep)
+	cln := db.NewOutboxCleaner(database, cfg.Inventory.Outbox.Mode == config.OutboxModeRelay, db.OutboxRetention(cfg.Inventory.Outbox.Retention))
 	han := handler.New(svc)
-	con := consumer.New(kfk.Reader, han)
-	mux := router.New(han)
+	con := consumer.New(kfk, han)
+	mux := router.New(han, cfg.Inventory.Admin.Tokens)
 	srv.Router.Handler = http.LoggingMiddleware(mux)
+	dsrv.Router.Handler = router.Debug()
+	rpc.RegisterInventoryServiceServer(gsrv.Router, handler.NewGRPCServer(svc, cfg.Inventory.Admin.Tokens))
+
+	var rel *db.OutboxRelay
+	switch outbox := cfg.Inventory.Outbox; outbox.Mode {
+	case config.OutboxModeRelay:
+		rel = db.NewOutboxRelay(database, cfg.Inventory.Kafka.Addr, cfg.Inventory.Kafka.ProducerTopic, outbox.Interval, outbox.BatchSize, outbox.SlotToDrop())
+	case config.OutboxModeDebezium, "":
+		// Kafka Connect publishes the outbox
+	default:
+		return nil, fmt.Errorf("unknown outbox mode %q", outbox.Mode)
+	}
 
 	app := &App{
+		Cleaner:  cln,
 		Config:   cfg,
 		Consumer: con,
-		DB:       db,
+		DB:       database,
+		Debug:    dsrv,
+		GRPC:     gsrv,
 		HTTP:     srv,
 		Kafka:    kfk,
 		Log:      log,
+		Relay:    rel,
 		Services: svc,
 	}
 
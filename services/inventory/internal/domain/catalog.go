@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxSKULen  = 100
+	maxNameLen = 255
+	// maxPrice and maxOnHand are the largest values the products table holds.
+	maxPrice  = 99999999.99
+	maxOnHand = math.MaxInt32
+)
+
+// skuPattern matches SKUs like WIDGET-A: upper case letters, digits and dashes.
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]*$`)
+
+// ProductChange is an admin's update of a product. Nil fields stay as they
+// are and Restock units are added on hand.
+type ProductChange struct {
+	Name    *string
+	Price   *float64
+	Restock int
+}
+
+// Empty reports whether the change changes nothing.
+func (c ProductChange) Empty() bool {
+	return c.Name == nil && c.Price == nil && c.Restock == 0
+}
+
+// ValidateNewProduct checks a product an admin is about to create.
+func ValidateNewProduct(p Product) error {
+	if err := validateSKU(p.SKU); err != nil {
+		return err
+	}
+	if err := validateName(p.SKU, p.Name); err != nil {
+		return err
+	}
+	if err := validatePrice(p.SKU, p.Price); err != nil {
+		return err
+	}
+	if p.OnHand < 0 || p.OnHand > maxOnHand {
+		return NewErrInvalidProduct(p.SKU, "on_hand", fmt.Sprintf("must be between 0 and %d", maxOnHand))
+	}
+	return nil
+}
+
+// ValidateProductChange checks a change of the product with the given SKU.
+func ValidateProductChange(sku string, c ProductChange) error {
+	if err := validateSKU(sku); err != nil {
+		return err
+	}
+	if c.Empty() {
+		return NewErrInvalidProduct(sku, "", "nothing to change")
+	}
+	if c.Name != nil {
+		if err := validateName(sku, *c.Name); err != nil {
+			return err
+		}
+	}
+	if c.Price != nil {
+		if err := validatePrice(sku, *c.Price); err != nil {
+			return err
+		}
+	}
+	if c.Restock < 0 || c.Restock > maxOnHand {
+		return NewErrInvalidProduct(sku, "restock", fmt.Sprintf("must be between 0 and %d", maxOnHand))
+	}
+	return nil
+}
+
+// ValidateRestock checks that restocking p by n units keeps its stock within
+// what the products table holds.
+func ValidateRestock(p Product, n int) error {
+	if int64(p.OnHand)+int64(n) > maxOnHand {
+		return NewErrInvalidProduct(p.SKU, "restock", fmt.Sprintf("would put more than %d on hand", maxOnHand))
+	}
+	return nil
+}
+
+func validateSKU(sku string) error {
+	if sku == "" {
+		return NewErrInvalidProduct(sku, "sku", "missing")
+	}
+	if len(sku) > maxSKULen || !skuPattern.MatchString(sku) {
+		return NewErrInvalidProduct(sku, "sku", fmt.Sprintf("must be up to %d upper case letters, digits and dashes", maxSKULen))
+	}
+	return nil
+}
+
+func validateName(sku, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return NewErrInvalidProduct(sku, "name", "missing")
+	}
+	if name != strings.TrimSpace(name) {
+		return NewErrInvalidProduct(sku, "name", "has leading or trailing spaces")
+	}
+	if utf8.RuneCountInString(name) > maxNameLen {
+		return NewErrInvalidProduct(sku, "name", fmt.Sprintf("longer than %d characters", maxNameLen))
+	}
+	return nil
+}
+
+func validatePrice(sku string, price float64) error {
+	if math.IsNaN(price) || price <= 0 || price > maxPrice {
+		return NewErrInvalidProduct(sku, "price", fmt.Sprintf("must be above 0 and at most %.2f", maxPrice))
+	}
+	if math.Round(price*100)/100 != price {
+		return NewErrInvalidProduct(sku, "price", "must be in whole cents")
+	}
+	return nil
+}
+
+// ErrInvalidProduct is returned for a product or a product change that fails
+// validation.
+var ErrInvalidProduct = errors.New("invalid product")
+
+type ErrInvalidProductField struct {
+	SKU    string
+	Field  string
+	Reason string
+}
+
+func (e *ErrInvalidProductField) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid product %s: %s", e.SKU, e.Reason)
+	}
+	return fmt.Sprintf("invalid product %s: %s %s", e.SKU, e.Field, e.Reason)
+}
+
+func (e *ErrInvalidProductField) Unwrap() error {
+	return ErrInvalidProduct
+}
+
+func NewErrInvalidProduct(sku, field, reason string) error {
+	return &ErrInvalidProductField{SKU: sku, Field: field, Reason: reason}
+}
+
+// ErrProductNotFound is returned for a SKU that is not in the catalog.
+var ErrProductNotFound = errors.New("product not found")
+
+type ErrProductNotFoundWithSKU struct {
+	SKU string
+}
+
+func (e *ErrProductNotFoundWithSKU) Error() string {
+	return fmt.Sprintf("product not found: %s", e.SKU)
+}
+
+func (e *ErrProductNotFoundWithSKU) Unwrap() error {
+	return ErrProductNotFound
+}
+
+func NewErrProductNotFound(sku string) error {
+	return &ErrProductNotFoundWithSKU{SKU: sku}
+}
+
+// ErrProductExists is returned when a product is created with a SKU that is
+// taken, archived products included.
+var ErrProductExists = errors.New("product already exists")
+
+type ErrProductExistsWithSKU struct {
+	SKU string
+}
+
+func (e *ErrProductExistsWithSKU) Error() string {
+	return fmt.Sprintf("product already exists: %s", e.SKU)
+}
+
+func (e *ErrProductExistsWithSKU) Unwrap() error {
+	return ErrProductExists
+}
+
+func NewErrProductExists(sku string) error {
+	return &ErrProductExistsWithSKU{SKU: sku}
+}
+
+// ErrProductArchived is returned when an archived product is changed.
+var ErrProductArchived = errors.New("product is archived")
+
+type ErrProductArchivedWithSKU struct {
+	SKU string
+}
+
+func (e *ErrProductArchivedWithSKU) Error() string {
+	return fmt.Sprintf("product is archived: %s", e.SKU)
+}
+
+func (e *ErrProductArchivedWithSKU) Unwrap() error {
+	return ErrProductArchived
+}
+
+func NewErrProductArchived(sku string) error {
+	return &ErrProductArchivedWithSKU{SKU: sku}
+}
@@ -1,19 +1,55 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
 type Status string
 
+// Product statuses are derived from the stock counters.
 const (
+	// StatusAvailable has units left to reserve.
 	StatusAvailable Status = "available"
-	StatusReserved  Status = "reserved"
-	StatusSold      Status = "sold"
+	// StatusReserved has no units left, but some are only held for unpaid orders.
+	StatusReserved Status = "reserved"
+	// StatusSold has every unit on hand sold.
+	StatusSold Status = "sold"
 )
 
+// Currency is the currency of all product prices.
+const Currency = "USD"
+
+// Product is a SKU and its stock. OnHand counts the units in the warehouse,
+// Reserved how many of them are held for unpaid orders and Sold the units
+// paid for so far. CreatedBy and UpdatedBy name the admin behind the first
+// and the last change; an archived product is no longer sold.
 type Product struct {
-	ID     int     `json:"id"`
-	Name   string  `json:"name"`
-	SKU    string  `json:"sku"`
-	Status string  `json:"status"`
-	Price  float64 `json:"price"`
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	SKU        string     `json:"sku"`
+	Status     string     `json:"status"`
+	Price      float64    `json:"price"`
+	OnHand     int        `json:"on_hand"`
+	Reserved   int        `json:"reserved"`
+	Sold       int        `json:"sold"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedBy  string     `json:"updated_by"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+// Available is how many units can still be reserved.
+func (p Product) Available() int {
+	return max(p.OnHand-p.Reserved, 0)
+}
+
+// Archived reports whether the product was taken off sale.
+func (p Product) Archived() bool {
+	return p.ArchivedAt != nil
 }
 
 func NewProduct(name, sku, status string, price float64) *Product {
@@ -24,3 +60,71 @@ func NewProduct(name, sku, status string, price float64) *Product {
 		Price:  price,
 	}
 }
+
+type ReservationStatus string
+
+const (
+	ReservationReserved ReservationStatus = "reserved"
+	ReservationSold     ReservationStatus = "sold"
+	ReservationReleased ReservationStatus = "released"
+)
+
+// Reservation is what an order holds of one SKU. Returned counts the sold
+// units that came back with a refund.
+type Reservation struct {
+	SKU      string
+	Quantity int
+	Returned int
+	Status   ReservationStatus
+}
+
+// Unreturned returns the units of the reservations that were sold and did not
+// come back yet. They are restocked when the whole payment of an order is
+// refunded, as happens when the payment raced a cancel: the payment sold the
+// units, so the release found nothing left to release.
+func Unreturned(reservations []Reservation) []Line {
+	var lines []Line
+	for _, r := range reservations {
+		if r.Status == ReservationSold && r.Quantity > r.Returned {
+			lines = append(lines, Line{SKU: r.SKU, Quantity: r.Quantity - r.Returned})
+		}
+	}
+	return lines
+}
+
+// Line is a quantity of a SKU, as ordered or refunded.
+type Line struct {
+	SKU      string
+	Quantity int
+}
+
+// Shortage is a line of an order that could not be reserved: Requested units
+// against the Available ones. An unknown SKU has none available.
+type Shortage struct {
+	SKU       string
+	Requested int
+	Available int
+}
+
+// ErrInsufficientStock is returned when an order cannot be reserved in full.
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+type ErrInsufficientStockFor struct {
+	Shortages []Shortage
+}
+
+func (e *ErrInsufficientStockFor) Error() string {
+	lines := make([]string, len(e.Shortages))
+	for i, s := range e.Shortages {
+		lines[i] = fmt.Sprintf("%s (requested %d, available %d)", s.SKU, s.Requested, s.Available)
+	}
+	return fmt.Sprintf("insufficient stock: %s", strings.Join(lines, ", "))
+}
+
+func (e *ErrInsufficientStockFor) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+func NewErrInsufficientStock(shortages []Shortage) error {
+	return &ErrInsufficientStockFor{Shortages: shortages}
+}
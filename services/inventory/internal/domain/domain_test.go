@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnreturned(t *testing.T) {
+	tests := []struct {
+		name         string
+		reservations []Reservation
+		want         []Line
+	}{
+		{
+			// the payment sold the units before the cancel released them,
+			// so the release left them sold and the refund brings them back
+			name: "payment raced the cancel",
+			reservations: []Reservation{
+				{SKU: "WIDGET-A", Quantity: 2, Status: ReservationSold},
+				{SKU: "WIDGET-B", Quantity: 1, Status: ReservationSold},
+			},
+			want: []Line{{SKU: "WIDGET-A", Quantity: 2}, {SKU: "WIDGET-B", Quantity: 1}},
+		},
+		{
+			name:         "cancel released the units first",
+			reservations: []Reservation{{SKU: "WIDGET-A", Quantity: 2, Status: ReservationReleased}},
+		},
+		{
+			name: "partly returned before",
+			reservations: []Reservation{
+				{SKU: "WIDGET-A", Quantity: 3, Returned: 1, Status: ReservationSold},
+				{SKU: "WIDGET-B", Quantity: 1, Returned: 1, Status: ReservationSold},
+			},
+			want: []Line{{SKU: "WIDGET-A", Quantity: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unreturned(tt.reservations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unreturned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+	// MaxQueryLen bounds the text a product listing is searched for.
+	MaxQueryLen = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortField is what products are listed by. Products with the same value
+// are listed by SKU.
+type SortField string
+
+const (
+	SortByName      SortField = "name"
+	SortByPrice     SortField = "price"
+	SortBySKU       SortField = "sku"
+	SortByCreatedAt SortField = "created_at"
+)
+
+// Sort is the order of a product listing.
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+// ParseSort reads a sort like "price" or "-created_at"; the empty sort lists
+// by name.
+func ParseSort(s string) (Sort, error) {
+	if s == "" {
+		return Sort{Field: SortByName}, nil
+	}
+	sort := Sort{Field: SortField(strings.TrimPrefix(s, "-")), Desc: strings.HasPrefix(s, "-")}
+	switch sort.Field {
+	case SortByName, SortByPrice, SortBySKU, SortByCreatedAt:
+		return sort, nil
+	default:
+		return Sort{}, fmt.Errorf("invalid sort %q", s)
+	}
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// Cursor is the keyset position of the last product of a page: its SKU and
+// its value of the field the listing is sorted by. A cursor only continues
+// a listing in the same order.
+type Cursor struct {
+	Sort      Sort
+	SKU       string
+	Name      string
+	Price     float64
+	CreatedAt time.Time
+}
+
+// CursorAt returns the cursor of p in a listing sorted by s.
+func CursorAt(s Sort, p Product) Cursor {
+	return Cursor{Sort: s, SKU: p.SKU, Name: p.Name, Price: p.Price, CreatedAt: p.CreatedAt}
+}
+
+// Value is the sort value of the cursor.
+func (c Cursor) Value() any {
+	switch c.Sort.Field {
+	case SortByPrice:
+		return c.Price
+	case SortByCreatedAt:
+		return c.CreatedAt
+	case SortBySKU:
+		return c.SKU
+	default:
+		return c.Name
+	}
+}
+
+func (c Cursor) Encode() string {
+	var value string
+	switch c.Sort.Field {
+	case SortByPrice:
+		value = strconv.FormatFloat(c.Price, 'f', -1, 64)
+	case SortByCreatedAt:
+		value = c.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByName:
+		value = c.Name
+	}
+	// SKUs have no |, names may, so the name goes last
+	raw := c.Sort.String() + "|" + c.SKU + "|" + value
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[1] == "" {
+		return nil, ErrInvalidCursor
+	}
+	sort, err := ParseSort(parts[0])
+	if err != nil || parts[0] == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{Sort: sort, SKU: parts[1]}
+	switch sort.Field {
+	case SortByPrice:
+		if c.Price, err = strconv.ParseFloat(parts[2], 64); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	case SortByCreatedAt:
+		if c.CreatedAt, err = time.Parse(time.RFC3339Nano, parts[2]); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	case SortByName:
+		c.Name = parts[2]
+	}
+	return c, nil
+}
+
+// ProductFilter selects products for listing. Zero fields do not filter;
+// Query matches part of the name or the SKU, ignoring case.
+type ProductFilter struct {
+	Status   Status
+	MinPrice float64
+	MaxPrice float64
+	Query    string
+	Sort     Sort
+	After    *Cursor
+	Limit    int
+}
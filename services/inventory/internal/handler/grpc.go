@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/axmz/go-saga-microservices/inventory-service/internal/service"
+	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer serves the InventoryService gRPC API on top of the same service
+// as the HTTP handlers.
+type GRPCServer struct {
+	rpc.UnimplementedInventoryServiceServer
+	Service *service.Service
+	// AdminTokens are the bearer tokens ResetProducts takes in the
+	// authorization metadata, as the HTTP admin endpoints do.
+	AdminTokens map[string]string
+}
+
+func NewGRPCServer(service *service.Service, adminTokens map[string]string) *GRPCServer {
+	return &GRPCServer{
+		Service:     service,
+		AdminTokens: adminTokens,
+	}
+}
+
+func (s *GRPCServer) GetProducts(ctx context.Context, req *httppb.GetProductsRequest) (*httppb.GetProductsResponse, error) {
+	filter, err := toProductFilter(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp, err := getProducts(ctx, s.Service, req.IfNoneMatch, filter)
+	if err != nil {
+		slog.Error("Inventory.GetProducts service error", "err", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetPrices(ctx context.Context, req *httppb.GetPricesRequest) (*httppb.GetPricesResponse, error) {
+	if len(req.Skus) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing sku")
+	}
+
+	prices, err := s.Service.GetPrices(ctx, req.Skus)
+	if err != nil {
+		slog.Error("Inventory.GetPrices service error", "err", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return toGetPricesResponse(req.Skus, prices), nil
+}
+
+func (s *GRPCServer) ResetProducts(ctx context.Context, _ *httppb.ResetProductsRequest) (*httppb.ResetProductsResponse, error) {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		authorization = md.Get("authorization")[0]
+	}
+	if _, err := httputils.Authenticate(s.AdminTokens, authorization); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if err := s.Service.ResetAllProducts(ctx); err != nil {
+		slog.Error("Inventory.ResetAllProducts service error", "err", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	slog.Info("Inventory.ResetAllProducts success")
+	return &httppb.ResetProductsResponse{}, nil
+}
@@ -2,14 +2,20 @@ package handler
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/domain"
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/service"
 	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
 	"github.com/axmz/go-saga-microservices/pkg/proto/events"
 	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
 	"github.com/segmentio/kafka-go"
@@ -26,19 +32,84 @@ func New(service *service.Service) *Handler {
 	}
 }
 
+// GetProducts lists the products a page at a time. The ETag of the response
+// names the catalog version, a request whose If-None-Match still names it is
+// answered with 304 Not Modified.
 func (h *Handler) GetProducts(w http.ResponseWriter, r *http.Request) {
 	slog.Info("Inventory.GetProducts request", "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
-	products, err := h.Service.GetProducts(r.Context())
+	req, err := httputils.ParseGetProductsRequest(r)
+	if err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+	filter, err := toProductFilter(req)
+	if err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+
+	resp, err := getProducts(r.Context(), h.Service, req.IfNoneMatch, filter)
 	if err != nil {
 		slog.Error("Inventory.GetProducts service error", "err", err)
 		httputils.ErrorInternal(w, err)
 		return
 	}
 
-	protoProducts := h.toProtoProducts(products)
+	w.Header().Set("ETag", resp.Etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if resp.NotModified {
+		slog.Info("Inventory.GetProducts not modified", "etag", resp.Etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	slog.Info("Inventory.GetProducts success", "count", len(resp.Products))
+	httputils.RespondProto(w, resp, http.StatusOK)
+}
+
+// getProducts answers a product listing for the HTTP and the gRPC API. When
+// ifNoneMatch still names the current catalog version, the page is not read.
+func getProducts(ctx context.Context, svc *service.Service, ifNoneMatch string, f domain.ProductFilter) (*httppb.GetProductsResponse, error) {
+	if ifNoneMatch != "" {
+		version, err := svc.CatalogVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if etag := catalogETag(version); httputils.ETagMatches(ifNoneMatch, etag) {
+			return &httppb.GetProductsResponse{Etag: etag, NotModified: true}, nil
+		}
+	}
+
+	products, next, version, err := svc.ListProducts(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return &httppb.GetProductsResponse{
+		Products:   toProtoProducts(products),
+		NextCursor: next,
+		Etag:       catalogETag(version),
+	}, nil
+}
+
+func catalogETag(version int64) string {
+	return `"` + strconv.FormatUint(uint64(version), 16) + `"`
+}
+
+func (h *Handler) GetPrices(w http.ResponseWriter, r *http.Request) {
+	skus := r.URL.Query()["sku"]
+	if len(skus) == 0 {
+		httputils.ErrorBadRequest(w, errors.New("missing sku"))
+		return
+	}
+
+	prices, err := h.Service.GetPrices(r.Context(), skus)
+	if err != nil {
+		slog.Error("Inventory.GetPrices service error", "err", err)
+		httputils.ErrorInternal(w, err)
+		return
+	}
 
-	slog.Info("Inventory.GetProducts success", "count", len(protoProducts))
-	h.respondWithGetProductsResponse(w, protoProducts)
+	httputils.RespondProto(w, toGetPricesResponse(skus, prices), http.StatusOK)
 }
 
 func (h *Handler) ResetAllProducts(w http.ResponseWriter, r *http.Request) {
@@ -52,84 +123,210 @@ func (h *Handler) ResetAllProducts(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *Handler) OrderEvents(ctx context.Context, event kafka.Message) {
-	data, ok := toProtoPayload(event.Value)
-	if !ok {
-		slog.Warn("OrderEvents: JSON outbox parse failed; dropping message")
+func (h *Handler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+	var req httppb.CreateProductRequest
+	if err := parseProtoBody(r, &req); err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+
+	product, err := h.Service.CreateProduct(r.Context(), httputils.Caller(r.Context()), domain.Product{
+		SKU:    sku,
+		Name:   req.Name,
+		Price:  req.Price,
+		OnHand: int(req.OnHand),
+	})
+	if err != nil {
+		respondWithProductError(w, "Inventory.CreateProduct", sku, err)
+		return
+	}
+
+	httputils.RespondProto(w, &httppb.CreateProductResponse{Product: toProtoProduct(product)}, http.StatusCreated)
+}
+
+func (h *Handler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+	var req httppb.UpdateProductRequest
+	if err := parseProtoBody(r, &req); err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+
+	product, err := h.Service.UpdateProduct(r.Context(), httputils.Caller(r.Context()), sku, domain.ProductChange{
+		Name:    req.Name,
+		Price:   req.Price,
+		Restock: int(req.Restock),
+	})
+	if err != nil {
+		respondWithProductError(w, "Inventory.UpdateProduct", sku, err)
 		return
 	}
 
-	var envelope events.OrderEventEnvelope
-	if err := proto.Unmarshal(data, &envelope); err != nil {
-		slog.Warn("OrderEvents: proto unmarshal failed", "err", err)
+	httputils.RespondProto(w, &httppb.UpdateProductResponse{Product: toProtoProduct(product)}, http.StatusOK)
+}
+
+func (h *Handler) ArchiveProduct(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+	product, err := h.Service.ArchiveProduct(r.Context(), httputils.Caller(r.Context()), sku)
+	if err != nil {
+		respondWithProductError(w, "Inventory.ArchiveProduct", sku, err)
 		return
 	}
+
+	httputils.RespondProto(w, &httppb.ArchiveProductResponse{Product: toProtoProduct(product)}, http.StatusOK)
+}
+
+func (h *Handler) OrderEvents(ctx context.Context, event kafka.Message) error {
+	envelope, err := codec.Decode[*events.OrderEventEnvelope](event)
+	if err != nil {
+		return err
+	}
 	switch evt := envelope.Event.(type) {
 	case *events.OrderEventEnvelope_OrderCreated:
 		slog.Info("Order event: created", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderCreated.Id)
-		h.Service.ReserveItems(ctx, evt.OrderCreated)
+		h.Service.ReserveItems(ctx, envelope.EventId, evt.OrderCreated)
+	case *events.OrderEventEnvelope_OrderExpired:
+		slog.Info("Order event: expired", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderExpired.Id)
+		h.Service.ReleaseReservedItems(ctx, envelope.EventId, evt.OrderExpired.Id)
+	case *events.OrderEventEnvelope_OrderCancelled:
+		slog.Info("Order event: cancelled", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderCancelled.Id)
+		h.Service.ReleaseReservedItems(ctx, envelope.EventId, evt.OrderCancelled.Id)
+	case *events.OrderEventEnvelope_OrderRefunded:
+		slog.Info("Order event: refunded", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderRefunded.Id)
+		h.Service.RestockRefundedItems(ctx, envelope.EventId, evt.OrderRefunded)
+	case *events.OrderEventEnvelope_RefundRequested:
+		// payment handles the refund, the items come back with OrderRefunded
 	default:
 		slog.Warn("OrderEvents: unknown or missing event type")
 	}
+	return nil
 }
 
-func (h *Handler) PaymentEvents(ctx context.Context, message kafka.Message) {
-	var envelope events.PaymentEventEnvelope
-	if err := proto.Unmarshal(message.Value, &envelope); err != nil {
-		slog.Warn("Failed to unmarshal PaymentEventEnvelope", "err", err)
-		return
+func (h *Handler) PaymentEvents(ctx context.Context, message kafka.Message) error {
+	envelope, err := codec.Decode[*events.PaymentEventEnvelope](message)
+	if err != nil {
+		return err
 	}
 
 	switch evt := envelope.Event.(type) {
 	case *events.PaymentEventEnvelope_PaymentSucceeded:
 		slog.Info("Payment event: succeeded", "topic", message.Topic, "partition", message.Partition, "offset", message.Offset, "orderId", evt.PaymentSucceeded.Id)
-		h.Service.MarkItemsSold(ctx, evt.PaymentSucceeded.Id)
+		h.Service.MarkItemsSold(ctx, envelope.EventId, evt.PaymentSucceeded.Id)
 	case *events.PaymentEventEnvelope_PaymentFailed:
 		slog.Info("Payment event: failed", "topic", message.Topic, "partition", message.Partition, "offset", message.Offset, "orderId", evt.PaymentFailed.Id)
-		h.Service.ReleaseReservedItems(ctx, evt.PaymentFailed.Id)
+		h.Service.ReleaseReservedItems(ctx, envelope.EventId, evt.PaymentFailed.Id)
+	case *events.PaymentEventEnvelope_PaymentRefunded:
+		slog.Info("Payment event: refunded", "topic", message.Topic, "partition", message.Partition, "offset", message.Offset, "orderId", evt.PaymentRefunded.Id)
+		h.Service.RestockPaidItems(ctx, envelope.EventId, evt.PaymentRefunded.Id)
 	default:
 		slog.Warn("Unknown or missing event type in envelope")
 	}
+	return nil
+}
+
+// REQ PROCESSING
+// toProductFilter checks a product listing request, taken from the query
+// string or from gRPC, and turns it into the filter of the products to list.
+func toProductFilter(req *httppb.GetProductsRequest) (domain.ProductFilter, error) {
+	f := domain.ProductFilter{
+		Status:   domain.Status(req.Status),
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		Query:    strings.TrimSpace(req.Q),
+		Limit:    int(req.Limit),
+	}
+	switch f.Status {
+	case "", domain.StatusAvailable, domain.StatusReserved, domain.StatusSold:
+	default:
+		return f, fmt.Errorf("invalid status: %s", req.Status)
+	}
+	if !(f.MinPrice >= 0) || !(f.MaxPrice >= 0) {
+		return f, errors.New("invalid price range: prices must not be negative")
+	}
+	if f.MaxPrice > 0 && f.MinPrice > f.MaxPrice {
+		return f, fmt.Errorf("invalid price range: min_price %v above max_price %v", f.MinPrice, f.MaxPrice)
+	}
+	if utf8.RuneCountInString(f.Query) > domain.MaxQueryLen {
+		return f, fmt.Errorf("q longer than %d characters", domain.MaxQueryLen)
+	}
+	if f.Limit < 0 {
+		return f, fmt.Errorf("invalid limit: %d", req.Limit)
+	}
+
+	var err error
+	if f.Sort, err = domain.ParseSort(req.Sort); err != nil {
+		return f, err
+	}
+	if req.Cursor != "" {
+		if f.After, err = domain.DecodeCursor(req.Cursor); err != nil {
+			return f, err
+		}
+		// a cursor only continues the listing it came from
+		if f.After.Sort != f.Sort {
+			return f, domain.ErrInvalidCursor
+		}
+	}
+	return f, nil
+}
+
+func parseProtoBody(r *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(body, msg)
 }
 
 // RESPONSES
-func (h *Handler) respondWithGetProductsResponse(w http.ResponseWriter, products []*httppb.Product) {
-	resp := &httppb.GetProductsResponse{Products: products}
-	httputils.RespondProto(w, resp, http.StatusOK)
+func respondWithProductError(w http.ResponseWriter, op, sku string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidProduct):
+		httputils.ErrorBadRequest(w, err)
+	case errors.Is(err, domain.ErrProductNotFound):
+		httputils.ErrorNotFound(w, err)
+	case errors.Is(err, domain.ErrProductExists), errors.Is(err, domain.ErrProductArchived):
+		httputils.ErrorConflict(w, err)
+	default:
+		slog.Error(op+" service error", "sku", sku, "err", err)
+		httputils.ErrorInternal(w, nil)
+	}
 }
 
 // MAPPERS
-func (h *Handler) toProtoProducts(products []domain.Product) []*httppb.Product {
+func toProtoProducts(products []domain.Product) []*httppb.Product {
 	out := make([]*httppb.Product, len(products))
 	for i, p := range products {
-		out[i] = &httppb.Product{
-			Id:     int64(p.ID),
-			Name:   p.Name,
-			Price:  p.Price,
-			Sku:    p.SKU,
-			Status: p.Status,
-		}
+		out[i] = toProtoProduct(p)
 	}
 	return out
 }
 
-func toProtoPayload(value []byte) ([]byte, bool) {
-	var outbox struct {
-		ID            string          `json:"id"`
-		AggregateType string          `json:"aggregate_type"`
-		AggregateID   string          `json:"aggregate_id"`
-		EventType     string          `json:"event_type"`
-		Payload       string          `json:"payload"`
-		Headers       json.RawMessage `json:"headers"`
-		CreatedAt     string          `json:"created_at"`
-		PublishedAt   *string         `json:"published_at"`
+func toProtoProduct(p domain.Product) *httppb.Product {
+	return &httppb.Product{
+		Id:                int64(p.ID),
+		Name:              p.Name,
+		Price:             p.Price,
+		Sku:               p.SKU,
+		Status:            p.Status,
+		AvailableQuantity: int32(p.Available()),
+		CreatedBy:         p.CreatedBy,
+		CreatedAt:         p.CreatedAt.Format(time.RFC3339),
+		UpdatedBy:         p.UpdatedBy,
+		UpdatedAt:         p.UpdatedAt.Format(time.RFC3339),
+		OnHand:            int32(p.OnHand),
+		Archived:          p.Archived(),
 	}
-	if err := json.Unmarshal(value, &outbox); err != nil {
-		return nil, false
-	}
-	b, err := base64.StdEncoding.DecodeString(outbox.Payload)
-	if err != nil {
-		return nil, false
+}
+
+// toGetPricesResponse lists the prices in the order of skus, leaving out
+// unknown SKUs.
+func toGetPricesResponse(skus []string, prices map[string]int64) *httppb.GetPricesResponse {
+	resp := &httppb.GetPricesResponse{Prices: make([]*httppb.ProductPrice, 0, len(prices))}
+	for _, sku := range skus {
+		if price, ok := prices[sku]; ok {
+			resp.Prices = append(resp.Prices, &httppb.ProductPrice{Sku: sku, PriceMinor: price, Currency: domain.Currency})
+		}
 	}
-	return b, true
+	return resp
 }
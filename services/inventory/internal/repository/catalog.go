@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/axmz/go-saga-microservices/inventory-service/internal/domain"
+)
+
+// productColumns are read by scanProduct.
+const productColumns = `id, name, sku, ` + productStatus + `, price, on_hand, reserved, sold,
+	created_by, created_at, updated_by, updated_at, archived_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row rowScanner) (domain.Product, error) {
+	var (
+		p          domain.Product
+		archivedAt sql.NullTime
+	)
+	err := row.Scan(&p.ID, &p.Name, &p.SKU, &p.Status, &p.Price, &p.OnHand, &p.Reserved, &p.Sold,
+		&p.CreatedBy, &p.CreatedAt, &p.UpdatedBy, &p.UpdatedAt, &archivedAt)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if archivedAt.Valid {
+		p.ArchivedAt = &archivedAt.Time
+	}
+	return p, nil
+}
+
+// WithTx runs fn in a transaction and commits it unless fn fails.
+func (r *Repository) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateProductTx adds p to the catalog. It fails with
+// domain.ErrProductExists when the SKU is taken, archived products included.
+func (r *Repository) CreateProductTx(ctx context.Context, tx *sql.Tx, p domain.Product) (domain.Product, error) {
+	created, err := scanProduct(tx.QueryRowContext(ctx, `
+		INSERT INTO products (name, sku, price, on_hand, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (sku) DO NOTHING
+		RETURNING `+productColumns,
+		p.Name, p.SKU, p.Price, p.OnHand, p.CreatedBy))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Product{}, domain.NewErrProductExists(p.SKU)
+	}
+	return created, err
+}
+
+// GetProductForUpdateTx reads the product and locks it until tx ends. It
+// fails with domain.ErrProductNotFound for an unknown SKU.
+func (r *Repository) GetProductForUpdateTx(ctx context.Context, tx *sql.Tx, sku string) (domain.Product, error) {
+	p, err := scanProduct(tx.QueryRowContext(ctx, `
+		SELECT `+productColumns+`
+		FROM products
+		WHERE sku = $1
+		FOR UPDATE
+	`, sku))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Product{}, domain.NewErrProductNotFound(sku)
+	}
+	return p, err
+}
+
+// UpdateProductTx applies the change to the product on behalf of actor.
+func (r *Repository) UpdateProductTx(ctx context.Context, tx *sql.Tx, sku string, change domain.ProductChange, actor string) (domain.Product, error) {
+	var (
+		name  sql.NullString
+		price sql.NullFloat64
+	)
+	if change.Name != nil {
+		name = sql.NullString{String: *change.Name, Valid: true}
+	}
+	if change.Price != nil {
+		price = sql.NullFloat64{Float64: *change.Price, Valid: true}
+	}
+
+	p, err := scanProduct(tx.QueryRowContext(ctx, `
+		UPDATE products
+		SET name = COALESCE($2, name),
+			price = COALESCE($3, price),
+			on_hand = on_hand + $4,
+			updated_by = $5,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE sku = $1
+		RETURNING `+productColumns,
+		sku, name, price, change.Restock, actor))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Product{}, domain.NewErrProductNotFound(sku)
+	}
+	return p, err
+}
+
+// ArchiveProductTx takes the product off sale on behalf of actor. Units held
+// for orders placed before stay reserved and can still be sold.
+func (r *Repository) ArchiveProductTx(ctx context.Context, tx *sql.Tx, sku, actor string) (domain.Product, error) {
+	p, err := scanProduct(tx.QueryRowContext(ctx, `
+		UPDATE products
+		SET archived_at = CURRENT_TIMESTAMP,
+			updated_by = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE sku = $1
+		RETURNING `+productColumns,
+		sku, actor))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Product{}, domain.NewErrProductNotFound(sku)
+	}
+	return p, err
+}
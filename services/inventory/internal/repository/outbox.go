@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/inventory-service/internal/domain"
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/google/uuid"
+)
+
+// newInventoryOutboxMessage wraps env in an outbox message keyed by
+// aggregateID, the order id of reservation events and the SKU of catalog
+// events. The message id doubles as the event id, so consumers see the same
+// id however often it is delivered.
+func newInventoryOutboxMessage(aggregateID string, env *events.InventoryEventEnvelope) (db.OutboxMessage, error) {
+	msg := db.OutboxMessage{
+		ID:            uuid.New(),
+		AggregateType: "inventory",
+		AggregateID:   aggregateID,
+		EventType:     codec.EventType(env),
+	}
+	env.EventId = msg.ID.String()
+	payload, err := codec.Marshal(env)
+	if err != nil {
+		return msg, err
+	}
+	msg.Payload = payload
+	return msg, nil
+}
+
+func (r *Repository) insertInventoryEvent(ctx context.Context, tx *sql.Tx, aggregateID string, env *events.InventoryEventEnvelope) error {
+	msg, err := newInventoryOutboxMessage(aggregateID, env)
+	if err != nil {
+		return err
+	}
+	return r.DB.InsertOutbox(ctx, tx, msg)
+}
+
+// ReservationSucceededTx writes InventoryReservationSucceeded for the order to
+// the outbox.
+func (r *Repository) ReservationSucceededTx(ctx context.Context, tx *sql.Tx, orderID string) error {
+	return r.insertInventoryEvent(ctx, tx, orderID, &events.InventoryEventEnvelope{
+		Event: &events.InventoryEventEnvelope_ReservationSucceeded{
+			ReservationSucceeded: &events.InventoryReservationSucceeded{
+				Id: orderID,
+			},
+		},
+	})
+}
+
+// ReservationFailedTx writes InventoryReservationFailed for the order to the
+// outbox, with the reason and the lines that were short.
+func (r *Repository) ReservationFailedTx(ctx context.Context, tx *sql.Tx, orderID, reason string, shortages []domain.Shortage) error {
+	unavailable := make([]*events.UnavailableItem, len(shortages))
+	for i, s := range shortages {
+		unavailable[i] = &events.UnavailableItem{
+			Sku:       s.SKU,
+			Requested: int32(s.Requested),
+			Available: int32(s.Available),
+		}
+	}
+	return r.insertInventoryEvent(ctx, tx, orderID, &events.InventoryEventEnvelope{
+		Event: &events.InventoryEventEnvelope_ReservationFailed{
+			ReservationFailed: &events.InventoryReservationFailed{
+				Id:          orderID,
+				Reason:      reason,
+				Unavailable: unavailable,
+			},
+		},
+	})
+}
+
+// ReservationReleasedTx writes InventoryReservationReleased for the order to
+// the outbox.
+func (r *Repository) ReservationReleasedTx(ctx context.Context, tx *sql.Tx, orderID string) error {
+	return r.insertInventoryEvent(ctx, tx, orderID, &events.InventoryEventEnvelope{
+		Event: &events.InventoryEventEnvelope_ReservationReleased{
+			ReservationReleased: &events.InventoryReservationReleased{
+				Id: orderID,
+			},
+		},
+	})
+}
+
+// ProductCreatedTx writes ProductCreated for the new product to the outbox.
+func (r *Repository) ProductCreatedTx(ctx context.Context, tx *sql.Tx, p domain.Product) error {
+	return r.insertInventoryEvent(ctx, tx, p.SKU, &events.InventoryEventEnvelope{
+		Event: &events.InventoryEventEnvelope_ProductCreated{
+			ProductCreated: &events.ProductCreated{
+				Product: toCatalogProduct(p),
+			},
+		},
+	})
+}
+
+// ProductUpdatedTx writes ProductUpdated for the changed product to the
+// outbox.
+func (r *Repository) ProductUpdatedTx(ctx context.Context, tx *sql.Tx, p domain.Product) error {
+	return r.insertInventoryEvent(ctx, tx, p.SKU, &events.InventoryEventEnvelope{
+		Event: &events.InventoryEventEnvelope_ProductUpdated{
+			ProductUpdated: &events.ProductUpdated{
+				Product: toCatalogProduct(p),
+			},
+		},
+	})
+}
+
+// PriceChangedTx writes PriceChanged to the outbox for a product whose price
+// went from oldPrice to its current one.
+func (r *Repository) PriceChangedTx(ctx context.Context, tx *sql.Tx, p domain.Product, oldPrice float64) error {
+	return r.insertInventoryEvent(ctx, tx, p.SKU, &events.InventoryEventEnvelope{
+		Event: &events.InventoryEventEnvelope_PriceChanged{
+			PriceChanged: &events.PriceChanged{
+				Sku:       p.SKU,
+				OldPrice:  oldPrice,
+				NewPrice:  p.Price,
+				Currency:  domain.Currency,
+				ChangedBy: p.UpdatedBy,
+				ChangedAt: p.UpdatedAt.Format(time.RFC3339),
+			},
+		},
+	})
+}
+
+func toCatalogProduct(p domain.Product) *events.CatalogProduct {
+	return &events.CatalogProduct{
+		Sku:       p.SKU,
+		Name:      p.Name,
+		Price:     p.Price,
+		Currency:  domain.Currency,
+		OnHand:    int32(p.OnHand),
+		Archived:  p.Archived(),
+		CreatedBy: p.CreatedBy,
+		CreatedAt: p.CreatedAt.Format(time.RFC3339),
+		UpdatedBy: p.UpdatedBy,
+		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+}
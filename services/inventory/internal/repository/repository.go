@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/domain"
 	"github.com/axmz/go-saga-microservices/lib/adapter/db"
@@ -18,106 +21,363 @@ func New(db *db.DB) *Repository {
 	return &Repository{DB: db}
 }
 
-func (r *Repository) GetProducts(ctx context.Context) ([]domain.Product, error) {
-	query := `SELECT id, name, sku, status, price
-			  FROM products
-			  ORDER BY name`
-	rows, err := r.DB.GetConn().QueryContext(ctx, query)
+// productStatus derives the status of a product from its stock counters.
+const productStatus = `
+	CASE
+		WHEN on_hand - reserved > 0 THEN 'available'
+		WHEN reserved > 0 THEN 'reserved'
+		ELSE 'sold'
+	END`
+
+// sortColumns are the columns of the fields products can be sorted by.
+var sortColumns = map[domain.SortField]string{
+	domain.SortByName:      "name",
+	domain.SortByPrice:     "price",
+	domain.SortBySKU:       "sku",
+	domain.SortByCreatedAt: "created_at",
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// catalogVersionQuery digests the SKU and the row version (xmin) of every
+// product. Each committed change of a product gives its row a new xmin, so
+// the digest changes whatever order writers commit in, and writers share no
+// row to keep it up to date.
+const catalogVersionQuery = `
+	SELECT coalesce(bit_xor(hashtextextended(sku || ':' || xmin::text, 0)), 0)
+	FROM products
+`
+
+// CatalogVersion returns the version of the catalog, which changes with every
+// change of a product.
+func (r *Repository) CatalogVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := r.DB.GetConn().QueryRowContext(ctx, catalogVersionQuery).Scan(&version)
+	return version, err
+}
+
+// ListProducts lists up to f.Limit products on sale that match f, archived
+// ones are left out. The products and the catalog version it returns are
+// read from the same snapshot.
+func (r *Repository) ListProducts(ctx context.Context, f domain.ProductFilter) ([]domain.Product, int64, error) {
+	var (
+		where = []string{"archived_at IS NULL"}
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Status != "" {
+		where = append(where, productStatus+" = "+arg(f.Status))
+	}
+	if f.MinPrice > 0 {
+		where = append(where, "price >= "+arg(f.MinPrice))
+	}
+	if f.MaxPrice > 0 {
+		where = append(where, "price <= "+arg(f.MaxPrice))
+	}
+	if f.Query != "" {
+		pattern := arg("%" + likeEscaper.Replace(f.Query) + "%")
+		where = append(where, fmt.Sprintf("(name ILIKE %s OR sku ILIKE %s)", pattern, pattern))
+	}
+	column, direction, cmp := sortColumns[f.Sort.Field], "ASC", ">"
+	if f.Sort.Desc {
+		direction, cmp = "DESC", "<"
+	}
+	if f.After != nil {
+		where = append(where, fmt.Sprintf("(%s, sku) %s (%s, %s)", column, cmp, arg(f.After.Value()), arg(f.After.SKU)))
+	}
+
+	q := `SELECT ` + productColumns + ` FROM products WHERE ` + strings.Join(where, " AND ") +
+		fmt.Sprintf(` ORDER BY %s %s, sku %s LIMIT %s`, column, direction, direction, arg(f.Limit))
+
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var version int64
+	if err := tx.QueryRowContext(ctx, catalogVersionQuery).Scan(&version); err != nil {
+		return nil, 0, fmt.Errorf("read catalog version: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list products: %w", err)
 	}
 	defer rows.Close()
 
-	var products []domain.Product
+	products := make([]domain.Product, 0, f.Limit)
 	for rows.Next() {
-		var product domain.Product
-		err := rows.Scan(&product.ID, &product.Name, &product.SKU, &product.Status, &product.Price)
+		product, err := scanProduct(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, fmt.Errorf("scan product: %w", err)
 		}
 		products = append(products, product)
 	}
-
-	return products, nil
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return products, version, nil
 }
 
-func (r *Repository) ReserveItems(ctx context.Context, event *events.OrderCreatedEvent) error {
-	items := event.GetItems()
-	skus := make([]string, len(items))
-	for i, item := range items {
-		skus[i] = item.GetId()
+// GetPrices returns the price in minor units of each of the given SKUs that is
+// on sale.
+func (r *Repository) GetPrices(ctx context.Context, skus []string) (map[string]int64, error) {
+	rows, err := r.DB.GetConn().QueryContext(ctx, `
+		SELECT sku, ROUND(price * 100)::BIGINT
+		FROM products
+		WHERE sku = ANY($1) AND archived_at IS NULL
+	`, pq.Array(skus))
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	const reserveQ = `
-		UPDATE products
-		SET status = $1,
-		order_id = $2
-		WHERE sku = ANY($3) AND status = $4
-	`
+	prices := make(map[string]int64, len(skus))
+	for rows.Next() {
+		var (
+			sku   string
+			price int64
+		)
+		if err := rows.Scan(&sku, &price); err != nil {
+			return nil, err
+		}
+		prices[sku] = price
+	}
+	return prices, rows.Err()
+}
 
-	res, err := r.DB.GetConn().ExecContext(
-		ctx,
-		reserveQ,
-		domain.StatusReserved,
-		event.Id,
-		pq.Array(skus),
-		domain.StatusAvailable,
-	)
+// inboxConsumer names the inventory service in the inbox of consumed events.
+const inboxConsumer = "inventory-service"
+
+// HandleOnce runs fn in a transaction unless the event was processed before,
+// see db.HandleOnce.
+func (r *Repository) HandleOnce(ctx context.Context, eventID string, fn func(tx *sql.Tx) error) (bool, error) {
+	return r.DB.HandleOnce(ctx, inboxConsumer, eventID, fn)
+}
+
+// ReserveItemsTx holds the ordered quantity of every SKU for the order, or
+// nothing at all. The product rows are locked in SKU order before anything is
+// checked, so concurrent orders for the same SKUs queue up instead of
+// overselling or deadlocking. When a line is short it returns an
+// *domain.ErrInsufficientStockFor listing every short line, before any row
+// was changed. Archived products have nothing available.
+func (r *Repository) ReserveItemsTx(ctx context.Context, tx *sql.Tx, event *events.OrderCreatedEvent) error {
+	lines := toLines(event.GetItems())
+	skus := make([]string, len(lines))
+	for i, line := range lines {
+		skus[i] = line.SKU
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT sku, on_hand - reserved
+		FROM products
+		WHERE sku = ANY($1) AND archived_at IS NULL
+		ORDER BY sku
+		FOR UPDATE
+	`, pq.Array(skus))
 	if err != nil {
 		return err
 	}
+	available := make(map[string]int, len(skus))
+	for rows.Next() {
+		var (
+			sku   string
+			units int
+		)
+		if err := rows.Scan(&sku, &units); err != nil {
+			rows.Close()
+			return err
+		}
+		available[sku] = units
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
-	rows, _ := res.RowsAffected()
-	if rows != int64(len(skus)) {
-		return fmt.Errorf("one or more SKUs were not available; reserved %d of %d", rows, len(skus))
+	var shortages []domain.Shortage
+	for _, line := range lines {
+		if units := available[line.SKU]; units < line.Quantity {
+			shortages = append(shortages, domain.Shortage{SKU: line.SKU, Requested: line.Quantity, Available: max(units, 0)})
+		}
+	}
+	if len(shortages) > 0 {
+		return domain.NewErrInsufficientStock(shortages)
 	}
 
-	return nil
-}
+	for _, line := range lines {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE products
+			SET reserved = reserved + $1, updated_at = CURRENT_TIMESTAMP
+			WHERE sku = $2
+		`, line.Quantity, line.SKU)
+		if err != nil {
+			return err
+		}
 
-func (r *Repository) ReleaseItems(ctx context.Context, orderID, productID string) {
-	query := `UPDATE products
-			  SET status = $1, updated_at = CURRENT_TIMESTAMP
-			  WHERE sku = $2 AND status = $3`
-	_, err := r.DB.GetConn().ExecContext(
-		ctx,
-		query,
-		domain.StatusAvailable,
-		productID,
-		domain.StatusReserved,
-	)
-	if err != nil {
-		// Handle error (e.g., log it)
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO reservations (order_id, sku, quantity, status)
+			VALUES ($1, $2, $3, $4)
+		`, event.Id, line.SKU, line.Quantity, domain.ReservationReserved)
+		if err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (r *Repository) MarkItemsSold(ctx context.Context, orderID string) error {
+// MarkItemsSoldTx turns the units reserved for the order into sold ones.
+func (r *Repository) MarkItemsSoldTx(ctx context.Context, tx *sql.Tx, orderID string) error {
 	const query = `
-		UPDATE products
-		SET status = 'sold'
-		WHERE order_id = $1
+		WITH sold AS (
+			UPDATE reservations
+			SET status = $1, updated_at = CURRENT_TIMESTAMP
+			WHERE order_id = $2 AND status = $3
+			RETURNING sku, quantity
+		)
+		UPDATE products p
+		SET on_hand = p.on_hand - s.quantity,
+			reserved = p.reserved - s.quantity,
+			sold = p.sold + s.quantity,
+			updated_at = CURRENT_TIMESTAMP
+		FROM sold s
+		WHERE p.sku = s.sku
 	`
 
-	_, err := r.DB.GetConn().ExecContext(ctx, query, orderID)
+	_, err := tx.ExecContext(ctx, query, domain.ReservationSold, orderID, domain.ReservationReserved)
 	return err
 }
 
-func (r *Repository) ReleaseReservedItems(ctx context.Context, orderID string) error {
+// ReleaseReservedItemsTx gives the units reserved for the order back. Units
+// that were sold already stay sold.
+func (r *Repository) ReleaseReservedItemsTx(ctx context.Context, tx *sql.Tx, orderID string) error {
 	const query = `
-		UPDATE products
-		SET status = $1, order_id = NULL
-		WHERE order_id = $2
+		WITH released AS (
+			UPDATE reservations
+			SET status = $1, updated_at = CURRENT_TIMESTAMP
+			WHERE order_id = $2 AND status = $3
+			RETURNING sku, quantity
+		)
+		UPDATE products p
+		SET reserved = p.reserved - r.quantity, updated_at = CURRENT_TIMESTAMP
+		FROM released r
+		WHERE p.sku = r.sku
 	`
 
-	_, err := r.DB.GetConn().ExecContext(ctx, query, domain.StatusAvailable, orderID)
+	_, err := tx.ExecContext(ctx, query, domain.ReservationReleased, orderID, domain.ReservationReserved)
 	return err
 }
 
+// RestockRefundedItemsTx puts refunded units of the order back on hand. A
+// line is restocked up to what was sold to the order and not returned yet.
+// It returns how many units were restocked.
+func (r *Repository) RestockRefundedItemsTx(ctx context.Context, tx *sql.Tx, orderID string, lines []domain.Line) (int64, error) {
+	var restocked int64
+	for _, line := range lines {
+		var left int
+		err := tx.QueryRowContext(ctx, `
+			SELECT quantity - returned
+			FROM reservations
+			WHERE order_id = $1 AND sku = $2 AND status = $3
+			FOR UPDATE
+		`, orderID, line.SKU, domain.ReservationSold).Scan(&left)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return restocked, err
+		}
+
+		units := min(line.Quantity, left)
+		if units <= 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE reservations
+			SET returned = returned + $1, updated_at = CURRENT_TIMESTAMP
+			WHERE order_id = $2 AND sku = $3
+		`, units, orderID, line.SKU); err != nil {
+			return restocked, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE products
+			SET on_hand = on_hand + $1, sold = sold - $1, updated_at = CURRENT_TIMESTAMP
+			WHERE sku = $2
+		`, units, line.SKU); err != nil {
+			return restocked, err
+		}
+		restocked += int64(units)
+	}
+	return restocked, nil
+}
+
+// GetReservationsTx returns what the order holds of each SKU, locking the
+// reservations until tx ends.
+func (r *Repository) GetReservationsTx(ctx context.Context, tx *sql.Tx, orderID string) ([]domain.Reservation, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT sku, quantity, returned, status
+		FROM reservations
+		WHERE order_id = $1
+		ORDER BY sku
+		FOR UPDATE
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []domain.Reservation
+	for rows.Next() {
+		var res domain.Reservation
+		if err := rows.Scan(&res.SKU, &res.Quantity, &res.Returned, &res.Status); err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, res)
+	}
+	return reservations, rows.Err()
+}
+
+// ResetAllProducts makes every unit ever sold or reserved available again and
+// forgets the reservations.
 func (r *Repository) ResetAllProducts(ctx context.Context) error {
-	const query = `
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reservations`); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
 		UPDATE products
-		SET status = $1, order_id = NULL
-	`
-	_, err := r.DB.GetConn().ExecContext(ctx, query, domain.StatusAvailable)
-	return err
+		SET on_hand = on_hand + sold, reserved = 0, sold = 0, updated_at = CURRENT_TIMESTAMP
+	`)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// toLines merges the order items per SKU; an item without quantity counts as one unit.
+func toLines(items []*events.Item) []domain.Line {
+	var lines []domain.Line
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		quantity := max(int(item.GetQuantity()), 1)
+		if i, ok := index[item.GetId()]; ok {
+			lines[i].Quantity += quantity
+			continue
+		}
+		index[item.GetId()] = len(lines)
+		lines = append(lines, domain.Line{SKU: item.GetId(), Quantity: quantity})
+	}
+	return lines
 }
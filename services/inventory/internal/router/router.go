@@ -1,14 +1,34 @@
 package router
 
 import (
+	"expvar"
 	"net/http"
 
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/handler"
+	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
 )
 
-func New(handlers *handler.Handler) *http.ServeMux {
+// New routes the inventory API. The product admin endpoints and the reset
+// take a bearer token of adminTokens, see httputils.BearerAuth.
+func New(handlers *handler.Handler, adminTokens map[string]string) *http.ServeMux {
+	admin := func(h http.HandlerFunc) http.Handler {
+		return httputils.BearerAuth(adminTokens, h)
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /products", handlers.GetProducts)
-	mux.HandleFunc("POST /products/reset", handlers.ResetAllProducts)
+	mux.HandleFunc("GET /prices", handlers.GetPrices)
+	mux.Handle("POST /products/reset", admin(handlers.ResetAllProducts))
+	mux.Handle("POST /products/{sku}", admin(handlers.CreateProduct))
+	mux.Handle("PUT /products/{sku}", admin(handlers.UpdateProduct))
+	mux.Handle("DELETE /products/{sku}", admin(handlers.ArchiveProduct))
+	return mux
+}
+
+// Debug serves the expvar metrics. It is mounted on the internal debug
+// listener, not on the public API.
+func Debug() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("GET /debug/vars", expvar.Handler())
 	return mux
 }
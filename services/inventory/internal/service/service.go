@@ -2,49 +2,270 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/domain"
-	"github.com/axmz/go-saga-microservices/inventory-service/internal/publisher"
 	"github.com/axmz/go-saga-microservices/inventory-service/internal/repository"
 	"github.com/axmz/go-saga-microservices/pkg/proto/events"
 )
 
 type Service struct {
-	Repo  *repository.Repository
-	Kafka *publisher.Publisher
+	Repo *repository.Repository
 }
 
-func New(repo *repository.Repository, kafka *publisher.Publisher) *Service {
+func New(repo *repository.Repository) *Service {
 	return &Service{
-		Repo:  repo,
-		Kafka: kafka,
+		Repo: repo,
 	}
 }
 
-func (s *Service) GetProducts(ctx context.Context) ([]domain.Product, error) {
-	return s.Repo.GetProducts(ctx)
+// CatalogVersion returns the current version of the catalog, see
+// ListProducts.
+func (s *Service) CatalogVersion(ctx context.Context) (int64, error) {
+	return s.Repo.CatalogVersion(ctx)
 }
 
-func (s *Service) ReserveItems(ctx context.Context, event *events.OrderCreatedEvent) {
-	if err := s.Repo.ReserveItems(ctx, event); err != nil {
-		s.Kafka.PublishInventoryReservationFailedEvent(event.Id)
+// ListProducts returns a page of the products that match f, the cursor of the
+// next page, empty on the last one, and the catalog version the page was read
+// at. The same filter at the same version gives the same page.
+func (s *Service) ListProducts(ctx context.Context, f domain.ProductFilter) ([]domain.Product, string, int64, error) {
+	if f.Limit <= 0 {
+		f.Limit = domain.DefaultListLimit
 	}
-	s.Kafka.PublishInventoryReservationSucceededEvent(event.Id)
+	if f.Limit > domain.MaxListLimit {
+		f.Limit = domain.MaxListLimit
+	}
+	if f.Sort.Field == "" {
+		f.Sort.Field = domain.SortByName
+	}
+	limit := f.Limit
+
+	// fetch one extra row to know whether there is a next page
+	f.Limit++
+	products, version, err := s.Repo.ListProducts(ctx, f)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if len(products) <= limit {
+		return products, "", version, nil
+	}
+
+	products = products[:limit]
+	return products, domain.CursorAt(f.Sort, products[limit-1]).Encode(), version, nil
+}
+
+// GetPrices returns the current price of each known SKU in minor units of
+// domain.Currency.
+func (s *Service) GetPrices(ctx context.Context, skus []string) (map[string]int64, error) {
+	return s.Repo.GetPrices(ctx, skus)
+}
+
+// ReserveItems handles an OrderCreated event. Like the other event handlers it
+// skips events whose id is already in the inbox. The order is reserved in full
+// or not at all, and exactly one of ReservationSucceeded or ReservationFailed
+// is written to the outbox for it, in the same transaction as the reservation.
+// Any other error rolls the transaction back, inbox entry included.
+func (s *Service) ReserveItems(ctx context.Context, eventID string, event *events.OrderCreatedEvent) {
+	var shortage *domain.ErrInsufficientStockFor
+	processed, err := s.Repo.HandleOnce(ctx, eventID, func(tx *sql.Tx) error {
+		err := s.Repo.ReserveItemsTx(ctx, tx, event)
+		// a failed reservation still counts as processed; nothing was
+		// reserved, so there is nothing to roll back
+		if errors.As(err, &shortage) {
+			return s.Repo.ReservationFailedTx(ctx, tx, event.Id, shortage.Error(), shortage.Shortages)
+		}
+		if err != nil {
+			return err
+		}
+		return s.Repo.ReservationSucceededTx(ctx, tx, event.Id)
+	})
+	if err != nil {
+		slog.Error("Failed to reserve items", "orderID", event.Id, "err", err)
+		return
+	}
+	if !processed {
+		slog.Info("Order created event already processed, skipping", "orderID", event.Id, "eventID", eventID)
+		return
+	}
+	if shortage != nil {
+		slog.Info("Order could not be reserved", "orderID", event.Id, "reason", shortage.Error())
+		return
+	}
+	slog.Info("Order reserved", "orderID", event.Id)
 }
 
-func (s *Service) MarkItemsSold(ctx context.Context, orderID string) {
-	if err := s.Repo.MarkItemsSold(ctx, orderID); err != nil {
+func (s *Service) MarkItemsSold(ctx context.Context, eventID, orderID string) {
+	processed, err := s.Repo.HandleOnce(ctx, eventID, func(tx *sql.Tx) error {
+		return s.Repo.MarkItemsSoldTx(ctx, tx, orderID)
+	})
+	if err != nil {
 		slog.Error("Failed to mark items as sold", "orderID", orderID, "err", err)
 		return
 	}
+	if !processed {
+		slog.Info("Payment event already processed, skipping", "orderID", orderID, "eventID", eventID)
+	}
 }
 
-func (s *Service) ReleaseReservedItems(ctx context.Context, orderID string) {
-	if err := s.Repo.ReleaseReservedItems(ctx, orderID); err != nil {
+func (s *Service) ReleaseReservedItems(ctx context.Context, eventID, orderID string) {
+	processed, err := s.Repo.HandleOnce(ctx, eventID, func(tx *sql.Tx) error {
+		if err := s.Repo.ReleaseReservedItemsTx(ctx, tx, orderID); err != nil {
+			return err
+		}
+		return s.Repo.ReservationReleasedTx(ctx, tx, orderID)
+	})
+	if err != nil {
 		slog.Error("Failed to release reserved items", "orderID", orderID, "err", err)
 		return
 	}
+	if !processed {
+		slog.Info("Release already processed, skipping", "orderID", orderID, "eventID", eventID)
+	}
+}
+
+// RestockRefundedItems handles an OrderRefunded event by putting the refunded
+// items back on sale.
+func (s *Service) RestockRefundedItems(ctx context.Context, eventID string, event *events.OrderRefundedEvent) {
+	lines := make([]domain.Line, len(event.GetItems()))
+	for i, item := range event.GetItems() {
+		lines[i] = domain.Line{SKU: item.GetId(), Quantity: max(int(item.GetQuantity()), 1)}
+	}
+
+	var restocked int64
+	processed, err := s.Repo.HandleOnce(ctx, eventID, func(tx *sql.Tx) error {
+		var err error
+		restocked, err = s.Repo.RestockRefundedItemsTx(ctx, tx, event.Id, lines)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to restock refunded items", "orderID", event.Id, "refundID", event.RefundId, "err", err)
+		return
+	}
+	if !processed {
+		slog.Info("Order refunded event already processed, skipping", "orderID", event.Id, "eventID", eventID)
+		return
+	}
+	slog.Info("Refunded items restocked", "orderID", event.Id, "refundID", event.RefundId, "lines", lines, "restocked", restocked)
+}
+
+// RestockPaidItems handles a PaymentRefunded event, sent when the whole payment
+// of an order that was cancelled or expired is refunded, by putting every unit
+// sold to the order and not returned yet back on sale.
+func (s *Service) RestockPaidItems(ctx context.Context, eventID, orderID string) {
+	var restocked int64
+	processed, err := s.Repo.HandleOnce(ctx, eventID, func(tx *sql.Tx) error {
+		reservations, err := s.Repo.GetReservationsTx(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+		restocked, err = s.Repo.RestockRefundedItemsTx(ctx, tx, orderID, domain.Unreturned(reservations))
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to restock items of refunded payment", "orderID", orderID, "err", err)
+		return
+	}
+	if !processed {
+		slog.Info("Payment refunded event already processed, skipping", "orderID", orderID, "eventID", eventID)
+		return
+	}
+	slog.Info("Items of refunded payment restocked", "orderID", orderID, "restocked", restocked)
+}
+
+// CreateProduct adds a product to the catalog on behalf of the admin actor
+// and writes ProductCreated to the outbox in the same transaction. It fails
+// with domain.ErrInvalidProduct or domain.ErrProductExists.
+func (s *Service) CreateProduct(ctx context.Context, actor string, p domain.Product) (domain.Product, error) {
+	if err := domain.ValidateNewProduct(p); err != nil {
+		return domain.Product{}, err
+	}
+	p.CreatedBy = actor
+
+	var created domain.Product
+	err := s.Repo.WithTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		created, err = s.Repo.CreateProductTx(ctx, tx, p)
+		if err != nil {
+			return err
+		}
+		return s.Repo.ProductCreatedTx(ctx, tx, created)
+	})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	slog.Info("Product created", "sku", created.SKU, "by", actor)
+	return created, nil
+}
+
+// UpdateProduct renames, reprices or restocks a product on behalf of the
+// admin actor. It writes ProductUpdated to the outbox, and PriceChanged as
+// well when the price changed. It fails with domain.ErrInvalidProduct,
+// domain.ErrProductNotFound or domain.ErrProductArchived.
+func (s *Service) UpdateProduct(ctx context.Context, actor, sku string, change domain.ProductChange) (domain.Product, error) {
+	if err := domain.ValidateProductChange(sku, change); err != nil {
+		return domain.Product{}, err
+	}
+
+	var updated domain.Product
+	err := s.Repo.WithTx(ctx, func(tx *sql.Tx) error {
+		current, err := s.Repo.GetProductForUpdateTx(ctx, tx, sku)
+		if err != nil {
+			return err
+		}
+		if current.Archived() {
+			return domain.NewErrProductArchived(sku)
+		}
+		if err := domain.ValidateRestock(current, change.Restock); err != nil {
+			return err
+		}
+
+		updated, err = s.Repo.UpdateProductTx(ctx, tx, sku, change, actor)
+		if err != nil {
+			return err
+		}
+		if err := s.Repo.ProductUpdatedTx(ctx, tx, updated); err != nil {
+			return err
+		}
+		if updated.Price != current.Price {
+			return s.Repo.PriceChangedTx(ctx, tx, updated, current.Price)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	slog.Info("Product updated", "sku", sku, "by", actor, "restock", change.Restock)
+	return updated, nil
+}
+
+// ArchiveProduct takes a product off sale on behalf of the admin actor and
+// writes ProductUpdated to the outbox. Archiving an archived product changes
+// nothing. It fails with domain.ErrProductNotFound.
+func (s *Service) ArchiveProduct(ctx context.Context, actor, sku string) (domain.Product, error) {
+	var archived domain.Product
+	err := s.Repo.WithTx(ctx, func(tx *sql.Tx) error {
+		current, err := s.Repo.GetProductForUpdateTx(ctx, tx, sku)
+		if err != nil {
+			return err
+		}
+		if current.Archived() {
+			archived = current
+			return nil
+		}
+
+		archived, err = s.Repo.ArchiveProductTx(ctx, tx, sku, actor)
+		if err != nil {
+			return err
+		}
+		return s.Repo.ProductUpdatedTx(ctx, tx, archived)
+	})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	slog.Info("Product archived", "sku", sku, "by", actor)
+	return archived, nil
 }
 
 func (s *Service) ResetAllProducts(ctx context.Context) error {
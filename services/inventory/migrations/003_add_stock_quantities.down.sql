@@ -0,0 +1,29 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(50) NOT NULL DEFAULT 'available'
+    CHECK (status IN ('available', 'reserved', 'sold'));
+ALTER TABLE products ADD COLUMN IF NOT EXISTS order_id UUID;
+
+-- a single unit per row again: a SKU with anything left stays available
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'products' AND column_name = 'on_hand') THEN
+        UPDATE products p SET
+            status = CASE
+                WHEN p.on_hand - p.reserved > 0 THEN 'available'
+                WHEN p.reserved > 0 THEN 'reserved'
+                ELSE 'sold'
+            END,
+            order_id = (
+                SELECT r.order_id FROM reservations r
+                WHERE r.sku = p.sku AND r.status = CASE WHEN p.reserved > 0 THEN 'reserved' ELSE 'sold' END
+                ORDER BY r.updated_at DESC
+                LIMIT 1
+            )
+        WHERE p.on_hand - p.reserved <= 0;
+    END IF;
+END $$;
+
+DROP TABLE IF EXISTS reservations;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_reserved_on_hand_check;
+ALTER TABLE products DROP COLUMN IF EXISTS on_hand;
+ALTER TABLE products DROP COLUMN IF EXISTS reserved;
+ALTER TABLE products DROP COLUMN IF EXISTS sold;
@@ -0,0 +1,43 @@
+-- Stock is counted per SKU: on_hand units are in the warehouse, reserved of
+-- them are held for unpaid orders and sold counts the units paid for so far.
+-- The available quantity is on_hand - reserved.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    ADD COLUMN IF NOT EXISTS reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    ADD COLUMN IF NOT EXISTS sold INTEGER NOT NULL DEFAULT 0 CHECK (sold >= 0);
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_reserved_on_hand_check;
+ALTER TABLE products ADD CONSTRAINT products_reserved_on_hand_check CHECK (reserved <= on_hand);
+
+-- One row per order and SKU; status can only be: 'reserved', 'sold', 'released'.
+-- returned counts the sold units that came back with a refund.
+CREATE TABLE IF NOT EXISTS reservations (
+    order_id UUID NOT NULL,
+    sku VARCHAR(100) NOT NULL REFERENCES products (sku),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    returned INTEGER NOT NULL DEFAULT 0 CHECK (returned >= 0 AND returned <= quantity),
+    status VARCHAR(20) NOT NULL DEFAULT 'reserved' CHECK (status IN ('reserved', 'sold', 'released')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_id, sku)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_sku_status ON reservations (sku, status);
+
+-- every product row used to be a single unit; a unit reserved without an
+-- order (like the WIDGET-R seed) has no reservation to release it and is put
+-- back on sale
+UPDATE products SET
+    on_hand = CASE WHEN status = 'sold' THEN 0 ELSE 1 END,
+    reserved = CASE WHEN status = 'reserved' AND order_id IS NOT NULL THEN 1 ELSE 0 END,
+    sold = CASE WHEN status = 'sold' THEN 1 ELSE 0 END;
+
+INSERT INTO reservations (order_id, sku, quantity, status)
+SELECT order_id, sku, 1, status
+FROM products
+WHERE order_id IS NOT NULL AND status IN ('reserved', 'sold')
+ON CONFLICT DO NOTHING;
+
+-- the status is derived from the counters now
+ALTER TABLE products DROP COLUMN IF EXISTS status;
+ALTER TABLE products DROP COLUMN IF EXISTS order_id;
@@ -0,0 +1,28 @@
+-- Events for the other services, written in the same transaction as the stock
+-- change they report and published to inventory.events by Debezium or by the
+-- inventory service's relay.
+CREATE TABLE IF NOT EXISTS outbox (
+    id UUID PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    headers JSONB DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox (published_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published_created_at ON outbox (created_at) WHERE published_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS outbox_archive (
+    id UUID PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    headers JSONB DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_price_check;
+ALTER TABLE products DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE products DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE products DROP COLUMN IF EXISTS created_by;
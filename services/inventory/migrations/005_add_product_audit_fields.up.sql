@@ -0,0 +1,10 @@
+-- Products are managed through the admin API: created_by and updated_by name
+-- the admin behind the last change, archived products are off sale but kept
+-- for the reservations and sales that refer to them.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT 'system',
+    ADD COLUMN IF NOT EXISTS updated_by TEXT NOT NULL DEFAULT 'system',
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_price_check;
+ALTER TABLE products ADD CONSTRAINT products_price_check CHECK (price > 0);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_created_at_sku;
+DROP INDEX IF EXISTS idx_products_price_sku;
+DROP INDEX IF EXISTS idx_products_name_sku;
@@ -0,0 +1,4 @@
+-- keyset pagination of the sorted listings; name order is the default
+CREATE INDEX IF NOT EXISTS idx_products_name_sku ON products (name, sku);
+CREATE INDEX IF NOT EXISTS idx_products_price_sku ON products (price, sku);
+CREATE INDEX IF NOT EXISTS idx_products_created_at_sku ON products (created_at, sku);
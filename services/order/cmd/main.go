@@ -10,6 +10,7 @@ import (
 	"github.com/axmz/go-graceful"
 	"github.com/axmz/go-saga-microservices/config"
 	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/grpc"
 	"github.com/axmz/go-saga-microservices/lib/adapter/http"
 	"github.com/axmz/go-saga-microservices/lib/adapter/kafka"
 	"github.com/axmz/go-saga-microservices/lib/logger"
@@ -55,8 +56,22 @@ func main() {
 		cancel()
 	}
 
+	// initialize debug server
+	dsrv, err := http.NewServer(http.Config(cfg.Order.Debug))
+	if err != nil {
+		slog.Error("Failed to initialize debug server:", "err", err)
+		cancel()
+	}
+
+	// initialize grpc server
+	gsrv, err := grpc.NewServer(grpc.Config(cfg.Order.GRPC))
+	if err != nil {
+		slog.Error("Failed to initialize gRPC server:", "err", err)
+		cancel()
+	}
+
 	// setup app
-	app, err := app.SetupApp(cfg, logger, db, srv, kafka)
+	app, err := app.SetupApp(cfg, logger, db, srv, dsrv, gsrv, kafka)
 	if err != nil {
 		slog.Error("Failed to initialize app:", "err", err)
 		cancel()
@@ -72,6 +87,26 @@ func main() {
 		}
 	}()
 
+	// Debug server
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.Debug.Run(); err != nil {
+			slog.Error("Debug server terminated:", "err", err)
+			cancel()
+		}
+	}()
+
+	// gRPC server
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.GRPC.Run(); err != nil {
+			slog.Error("gRPC server terminated:", "err", err)
+			cancel()
+		}
+	}()
+
 	// Kafka consumer
 	wg.Add(1)
 	go func() {
@@ -82,12 +117,60 @@ func main() {
 		}
 	}()
 
+	// Order status watcher
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.Watcher.Start(ctx); err != nil {
+			slog.Error("Order status watcher terminated:", "err", err)
+			cancel()
+		}
+	}()
+
+	// Order expiry sweeper
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.Sweeper.Start(ctx); err != nil {
+			slog.Error("Order expiry sweeper terminated:", "err", err)
+			cancel()
+		}
+	}()
+
+	// Outbox retention cleanup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.Cleaner.Start(ctx); err != nil {
+			slog.Error("Outbox cleanup terminated:", "err", err)
+			cancel()
+		}
+	}()
+
+	// Outbox relay, when Debezium is not used
+	if app.Relay != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := app.Relay.Start(ctx); err != nil {
+				slog.Error("Outbox relay terminated:", "err", err)
+				cancel()
+			}
+		}()
+	}
+
 	// Wait for shutdown signal or context cancellation
-	<-graceful.Shutdown(ctx, app.Config.GracefulTimeout, map[string]graceful.Operation{
-		"kafka":       app.Kafka.Shutdown,
-		"database":    app.DB.Shutdown,
-		"http-server": app.HTTP.Shutdown,
-	})
+	operations := map[string]graceful.Operation{
+		"kafka":        app.Kafka.Shutdown,
+		"database":     app.DB.Shutdown,
+		"http-server":  app.HTTP.Shutdown,
+		"debug-server": app.Debug.Shutdown,
+		"grpc-server":  app.GRPC.Shutdown,
+	}
+	if app.Relay != nil {
+		operations["outbox-relay"] = app.Relay.Shutdown
+	}
+	<-graceful.Shutdown(ctx, app.Config.GracefulTimeout, operations)
 
 	wg.Wait()
 	app.Log.Warn("Application stopped")
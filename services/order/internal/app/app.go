@@ -1,57 +1,88 @@
 package app
 
 import (
+	"fmt"
 	"log/slog"
 
 	"github.com/axmz/go-saga-microservices/config"
 	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/grpc"
 	"github.com/axmz/go-saga-microservices/lib/adapter/http"
 	"github.com/axmz/go-saga-microservices/lib/adapter/kafka"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
+	"github.com/axmz/go-saga-microservices/services/order/internal/client"
 	"github.com/axmz/go-saga-microservices/services/order/internal/consumer"
 	"github.com/axmz/go-saga-microservices/services/order/internal/handler"
-	"github.com/axmz/go-saga-microservices/services/order/internal/publisher"
 	"github.com/axmz/go-saga-microservices/services/order/internal/repository"
 	"github.com/axmz/go-saga-microservices/services/order/internal/router"
 	"github.com/axmz/go-saga-microservices/services/order/internal/service"
-	"github.com/axmz/go-saga-microservices/services/order/internal/sync"
+	"github.com/axmz/go-saga-microservices/services/order/internal/sweeper"
+	"github.com/axmz/go-saga-microservices/services/order/internal/watcher"
 )
 
 type App struct {
-	Config    *config.Config
-	Consumer  *consumer.Consumer
-	DB        *db.DB
-	HTTP      *http.Server
-	Kafka     *kafka.Broker
-	Log       *slog.Logger
-	Publisher *publisher.Publisher
-	Repo      *repository.Repository
-	Services  *service.Service
+	Cleaner  *db.OutboxCleaner
+	Config   *config.Config
+	Consumer *consumer.Consumer
+	DB       *db.DB
+	Debug    *http.Server
+	GRPC     *grpc.Server
+	HTTP     *http.Server
+	Kafka    *kafka.Broker
+	Log      *slog.Logger
+	Relay    *db.OutboxRelay
+	Repo     *repository.Repository
+	Services *service.Service
+	Sweeper  *sweeper.Sweeper
+	Watcher  *watcher.Watcher
 }
 
 func SetupApp(
 	cfg *config.Config,
 	log *slog.Logger,
-	db *db.DB,
+	database *db.DB,
 	srv *http.Server,
+	dsrv *http.Server,
+	gsrv *grpc.Server,
 	kfk *kafka.Broker,
 ) (*App, error) {
-	rep := repository.New(db)
-	pub := publisher.New(kfk.Writer)
-	syn := sync.New()
-	svc := service.New(rep, pub, syn)
-	han := handler.New(svc)
-	con := consumer.New(kfk.Reader, han)
+	rep := repository.New(database)
+	wat := watcher.New(database)
+	inv := client.NewHTTPInventoryClient(cfg.Inventory.HTTP.URL())
+	svc := service.New(cfg, rep, wat, inv)
+	swp := sweeper.New(database, svc, cfg.Order.Expiry.Interval)
+	cln := db.NewOutboxCleaner(database, cfg.Order.Outbox.Mode == config.OutboxModeRelay, db.OutboxRetention(cfg.Order.Outbox.Retention))
+	han := handler.New(svc, cfg.Storefront.HTTP.Host)
+	con := consumer.New(kfk, han)
 	mux := router.New(svc, han)
 	srv.Router.Handler = http.LoggingMiddleware(mux)
+	dsrv.Router.Handler = router.Debug()
+	rpc.RegisterOrderServiceServer(gsrv.Router, handler.NewGRPCServer(svc))
+
+	var rel *db.OutboxRelay
+	switch outbox := cfg.Order.Outbox; outbox.Mode {
+	case config.OutboxModeRelay:
+		rel = db.NewOutboxRelay(database, cfg.Order.Kafka.Addr, cfg.Order.Kafka.ProducerTopic, outbox.Interval, outbox.BatchSize, outbox.SlotToDrop())
+	case config.OutboxModeDebezium, "":
+		// Kafka Connect publishes the outbox
+	default:
+		return nil, fmt.Errorf("unknown outbox mode %q", outbox.Mode)
+	}
 
 	app := &App{
+		Cleaner:  cln,
 		Config:   cfg,
 		Consumer: con,
-		DB:       db,
+		DB:       database,
+		Debug:    dsrv,
+		GRPC:     gsrv,
 		HTTP:     srv,
 		Kafka:    kfk,
 		Log:      log,
+		Relay:    rel,
 		Services: svc,
+		Sweeper:  swp,
+		Watcher:  wat,
 	}
 
 	slog.Info("Application initialized", slog.String("env", app.Config.Env))
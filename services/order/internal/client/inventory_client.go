@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"google.golang.org/protobuf/proto"
+)
+
+// inventoryTimeout bounds a call to the inventory service.
+const inventoryTimeout = 5 * time.Second
+
+type InventoryClient interface {
+	GetPrices(ctx context.Context, skus []string) (*httppb.GetPricesResponse, error)
+}
+
+type HTTPInventoryClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPInventoryClient(baseURL string) *HTTPInventoryClient {
+	return &HTTPInventoryClient{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: inventoryTimeout},
+	}
+}
+
+func (c *HTTPInventoryClient) GetPrices(ctx context.Context, skus []string) (*httppb.GetPricesResponse, error) {
+	q := url.Values{"sku": skus}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/prices?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("inventory service returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var protoResp httppb.GetPricesResponse
+	if err := proto.Unmarshal(body, &protoResp); err != nil {
+		return nil, err
+	}
+
+	return &protoResp, nil
+}
@@ -0,0 +1,19 @@
+package domain
+
+// Customer is who placed an order and where it goes.
+type Customer struct {
+	ID              string  `json:"id"`
+	Email           string  `json:"email"`
+	ShippingAddress Address `json:"shipping_address"`
+}
+
+// Address is a postal address. Country is an ISO 3166-1 alpha-2 code.
+type Address struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
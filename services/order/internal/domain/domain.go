@@ -14,11 +14,21 @@ const (
 	StatusPending         Status = "Pending"
 	StatusAwaitingPayment Status = "AwaitingPayment"
 	StatusPaid            Status = "Paid"
+	StatusCompensating    Status = "Compensating"
 	StatusFailed          Status = "Failed"
+	StatusCancelling      Status = "Cancelling"
+	StatusCancelled       Status = "Cancelled"
+	StatusRefunding       Status = "Refunding"
+	StatusRefunded        Status = "Refunded"
+	StatusShipped         Status = "Shipped"
+	StatusDelivered       Status = "Delivered"
 )
 
 var ErrOrderNotFound = errors.New("order not found")
 
+// ErrOrderPending is returned when the saga did not get past Pending in time.
+var ErrOrderPending = errors.New("order is still pending")
+
 type ErrOrderNotFoundWithID struct {
 	OrderID string
 }
@@ -35,26 +45,108 @@ func NewErrOrderNotFound(id string) error {
 	return &ErrOrderNotFoundWithID{OrderID: id}
 }
 
+// ErrUnknownProduct is returned when an order names a SKU without a price.
+var ErrUnknownProduct = errors.New("unknown product")
+
+type ErrUnknownProductWithSKU struct {
+	SKU string
+}
+
+func (e *ErrUnknownProductWithSKU) Error() string {
+	return fmt.Sprintf("unknown product: %s", e.SKU)
+}
+
+func (e *ErrUnknownProductWithSKU) Unwrap() error {
+	return ErrUnknownProduct
+}
+
+func NewErrUnknownProduct(sku string) error {
+	return &ErrUnknownProductWithSKU{SKU: sku}
+}
+
+// ErrConcurrentModification is returned when an order changed between being
+// read and being written. Reloading the order and trying again is safe.
+var ErrConcurrentModification = errors.New("order was modified concurrently")
+
+type ErrConcurrentModificationWithID struct {
+	OrderID string
+	Version int64
+}
+
+func (e *ErrConcurrentModificationWithID) Error() string {
+	return fmt.Sprintf("order %s was modified concurrently, version %d is stale", e.OrderID, e.Version)
+}
+
+func (e *ErrConcurrentModificationWithID) Unwrap() error {
+	return ErrConcurrentModification
+}
+
+func NewErrConcurrentModification(id string, version int64) error {
+	return &ErrConcurrentModificationWithID{OrderID: id, Version: version}
+}
+
+// DefaultCurrency is used for items that do not name a currency.
+const DefaultCurrency = "USD"
+
 type Order struct {
-	ID        string    `json:"id"`
-	Items     []Item    `json:"items"`
-	Status    Status    `json:"status"`
+	ID       string   `json:"id"`
+	Customer Customer `json:"customer"`
+	Items    []Item   `json:"items"`
+	Status   Status   `json:"status"`
+	// Subtotal and Total are in minor units of Currency, cents for USD.
+	Subtotal  int64     `json:"subtotal_minor"`
+	Total     int64     `json:"total_minor"`
+	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version is bumped on every update and guards against lost updates.
+	Version int64 `json:"version"`
+	// Shipment is set once the shipping service booked the order with a carrier.
+	Shipment *Shipment `json:"shipment,omitempty"`
 }
 
+// Item is one order line. ProductID holds the product SKU; the unit price is a
+// snapshot in minor units taken when the order was placed.
 type Item struct {
 	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price_minor"`
+	Currency  string `json:"currency"`
+	// RefundedQuantity is how much of the line has been refunded so far.
+	RefundedQuantity int `json:"refunded_quantity"`
 }
 
-func NewOrder(items []Item) *Order {
+// LineTotal is the price of the line in minor units.
+func (i Item) LineTotal() int64 {
+	return i.UnitPrice * int64(i.Quantity)
+}
+
+// OwnedBy reports whether the order was placed by the given customer.
+func (o *Order) OwnedBy(customerID string) bool {
+	return o.Customer.ID == customerID
+}
+
+// NewOrder creates a pending order from priced items, which share the one
+// currency inventory prices its products in. The total equals the subtotal as long as there are no
+// taxes, discounts or shipping costs.
+func NewOrder(customer Customer, items []Item) *Order {
 	id := uuid.New().String()
 	now := time.Now()
-	return &Order{
+	o := &Order{
 		ID:        id,
+		Customer:  customer,
 		Items:     items,
 		Status:    StatusPending,
+		Currency:  DefaultCurrency,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	if len(items) > 0 {
+		o.Currency = items[0].Currency
+	}
+	for _, item := range items {
+		o.Subtotal += item.LineTotal()
+	}
+	o.Total = o.Subtotal
+	return o
 }
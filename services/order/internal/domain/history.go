@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Cause identifies what triggered a status change: the Kafka message consumed
+// from another service, or the outbox message the order service wrote itself.
+type Cause struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	// EventID is the envelope's event id of a consumed message.
+	EventID  string
+	OutboxID string
+}
+
+// StatusChange is one entry of an order's status history.
+type StatusChange struct {
+	ID         int64     `json:"id"`
+	OrderID    string    `json:"order_id"`
+	FromStatus Status    `json:"from_status"`
+	ToStatus   Status    `json:"to_status"`
+	Event      Event     `json:"event"`
+	Cause      Cause     `json:"cause"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+
+// ErrIdempotencyKeyClaimed is returned when a concurrent request with the same
+// key and the same body claimed the key first; its order is the answer.
+var ErrIdempotencyKeyClaimed = errors.New("idempotency key was claimed by the same request")
+
+type ErrIdempotencyKeyClaimedBy struct {
+	OrderID string
+}
+
+func (e *ErrIdempotencyKeyClaimedBy) Error() string {
+	return fmt.Sprintf("idempotency key was claimed by the same request: order %s", e.OrderID)
+}
+
+func (e *ErrIdempotencyKeyClaimedBy) Unwrap() error {
+	return ErrIdempotencyKeyClaimed
+}
+
+func NewErrIdempotencyKeyClaimed(orderID string) error {
+	return &ErrIdempotencyKeyClaimedBy{OrderID: orderID}
+}
+
+// Idempotency ties a create request to the Idempotency-Key it was sent with.
+type Idempotency struct {
+	Key         string
+	RequestHash string
+	// ExpiredBefore is the retention cutoff: keys claimed earlier are forgotten
+	// and may be used again.
+	ExpiredBefore time.Time
+}
+
+// IdempotentResponse is the response stored for a key so replays get it back.
+type IdempotentResponse struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
+}
+
+// IdempotencyRecord is a claimed key. Response is nil while the first request
+// is still being served, or when it never got to store one; OrderID is set
+// either way.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	OrderID     string
+	Response    *IdempotentResponse
+	CreatedAt   time.Time
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefundStatus string
+
+const (
+	RefundRequested RefundStatus = "Requested"
+	RefundSucceeded RefundStatus = "Succeeded"
+	RefundFailed    RefundStatus = "Failed"
+)
+
+// ErrInvalidRefund is returned when a refund asks for more than is left to
+// refund of an order, or for items the order does not have.
+var ErrInvalidRefund = errors.New("invalid refund")
+
+type ErrInvalidRefundWithReason struct {
+	OrderID string
+	Reason  string
+}
+
+func (e *ErrInvalidRefundWithReason) Error() string {
+	return fmt.Sprintf("invalid refund of order %s: %s", e.OrderID, e.Reason)
+}
+
+func (e *ErrInvalidRefundWithReason) Unwrap() error {
+	return ErrInvalidRefund
+}
+
+func NewErrInvalidRefund(orderID, reason string) error {
+	return &ErrInvalidRefundWithReason{OrderID: orderID, Reason: reason}
+}
+
+// ErrRefundNotFound is returned for a payment reply naming an unknown refund.
+var ErrRefundNotFound = errors.New("refund not found")
+
+type ErrRefundNotFoundWithID struct {
+	RefundID string
+}
+
+func (e *ErrRefundNotFoundWithID) Error() string {
+	return fmt.Sprintf("refund not found: %s", e.RefundID)
+}
+
+func (e *ErrRefundNotFoundWithID) Unwrap() error {
+	return ErrRefundNotFound
+}
+
+func NewErrRefundNotFound(id string) error {
+	return &ErrRefundNotFoundWithID{RefundID: id}
+}
+
+// RefundLine is the part of an order line given back. A zero Quantity in a
+// request stands for whatever is left of the line. Amount is in minor units.
+type RefundLine struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Amount    int64  `json:"amount_minor"`
+}
+
+// Refund gives back some or all items of a paid order. Full is set when the
+// refund leaves nothing of the order unrefunded. Amount is in minor units of
+// Currency.
+type Refund struct {
+	ID            string       `json:"id"`
+	OrderID       string       `json:"order_id"`
+	Status        RefundStatus `json:"status"`
+	Lines         []RefundLine `json:"lines"`
+	Amount        int64        `json:"amount_minor"`
+	Currency      string       `json:"currency"`
+	Full          bool         `json:"full"`
+	Reason        string       `json:"reason,omitempty"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// NewRefund creates a requested refund of the given lines of o, which must be
+// loaded with its items. Without lines everything not refunded yet is refunded.
+// Lines are priced at the unit price the order was placed with.
+func NewRefund(o *Order, lines []RefundLine, reason string) (*Refund, error) {
+	remaining := make(map[string]Item, len(o.Items))
+	left := 0
+	for _, item := range o.Items {
+		remaining[item.ProductID] = item
+		left += item.Quantity - item.RefundedQuantity
+	}
+	if left == 0 {
+		return nil, NewErrInvalidRefund(o.ID, "nothing left to refund")
+	}
+
+	if len(lines) == 0 {
+		for _, item := range o.Items {
+			if item.Quantity > item.RefundedQuantity {
+				lines = append(lines, RefundLine{ProductID: item.ProductID})
+			}
+		}
+	}
+
+	now := time.Now()
+	refund := &Refund{
+		ID:        uuid.New().String(),
+		OrderID:   o.ID,
+		Status:    RefundRequested,
+		Currency:  o.Currency,
+		Reason:    reason,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		item, ok := remaining[line.ProductID]
+		if !ok {
+			return nil, NewErrInvalidRefund(o.ID, fmt.Sprintf("order has no item %s", line.ProductID))
+		}
+		if seen[line.ProductID] {
+			return nil, NewErrInvalidRefund(o.ID, fmt.Sprintf("item %s refunded twice", line.ProductID))
+		}
+		seen[line.ProductID] = true
+
+		rest := item.Quantity - item.RefundedQuantity
+		quantity := line.Quantity
+		if quantity == 0 {
+			quantity = rest
+		}
+		if quantity < 0 || quantity > rest {
+			return nil, NewErrInvalidRefund(o.ID, fmt.Sprintf("%d of item %s left to refund, %d requested", rest, line.ProductID, line.Quantity))
+		}
+		if quantity == 0 {
+			return nil, NewErrInvalidRefund(o.ID, fmt.Sprintf("item %s already refunded", line.ProductID))
+		}
+
+		amount := item.UnitPrice * int64(quantity)
+		refund.Lines = append(refund.Lines, RefundLine{ProductID: line.ProductID, Quantity: quantity, Amount: amount})
+		refund.Amount += amount
+		left -= quantity
+	}
+	refund.Full = left == 0
+	return refund, nil
+}
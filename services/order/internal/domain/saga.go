@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Event is a saga trigger received from another service.
+type Event string
+
+const (
+	EventReservationSucceeded Event = "InventoryReservationSucceeded"
+	EventReservationFailed    Event = "InventoryReservationFailed"
+	EventReservationReleased  Event = "InventoryReservationReleased"
+	EventPaymentSucceeded     Event = "PaymentSucceeded"
+	EventPaymentFailed        Event = "PaymentFailed"
+	EventPaymentRefunded      Event = "PaymentRefunded"
+	EventExpired              Event = "OrderExpired"
+	EventCancelled            Event = "OrderCancelled"
+	EventRefundRequested      Event = "RefundRequested"
+	EventRefundSucceeded      Event = "RefundSucceeded"
+	EventRefundFailed         Event = "RefundFailed"
+	EventShipmentCreated      Event = "ShipmentCreated"
+	EventShipmentDispatched   Event = "ShipmentDispatched"
+	EventShipmentDelivered    Event = "ShipmentDelivered"
+	// EventPartialRefundSucceeded is a RefundSucceeded that leaves part of the
+	// order unrefunded.
+	EventPartialRefundSucceeded Event = "PartialRefundSucceeded"
+	// EventTimedOut is raised by the sweeper for an order that waited too long
+	// for a reply; the command it waits on is published again.
+	EventTimedOut Event = "SagaTimedOut"
+
+	// EventCreated only appears in the status history, it is not a saga trigger.
+	EventCreated Event = "OrderCreated"
+)
+
+// transitions is the order saga state machine. Anything not listed here is illegal.
+//
+//	Pending --ReservationSucceeded--> AwaitingPayment --PaymentSucceeded--> Paid
+//	   |                                    |
+//	   ReservationFailed                    PaymentFailed / OrderExpired
+//	   v                                    v
+//	 Failed <--ReservationReleased-- Compensating
+//
+// An order stuck in Pending is expired into Compensating as well, since the
+// reservation may have been made without its reply reaching us. A payment
+// that arrives after the order expired is refunded by the payment service and
+// accepted without changing the status.
+//
+// A customer cancel takes Pending or AwaitingPayment into Cancelling, which ends
+// in Cancelled once inventory released the items. Replies that were already in
+// flight when the cancel happened are accepted without changing the status;
+// the payment service refunds a payment that raced the cancel.
+//
+// A customer refund takes Paid into Refunding until payment replies. A refund
+// of everything that is left ends in Refunded; a partial or failed one goes
+// back to Paid, from where the rest can be refunded later. When the carrier
+// picked the order up while the refund was open, it goes to Shipped or
+// Delivered instead, as the shipment says.
+//
+// Compensating, Cancelling and Refunding each wait on a single reply. An order
+// that waits too long times out into the same status, and the release or the
+// refund is asked for again, so that a lost reply or a failed release does not
+// leave it there for good.
+//
+// A paid order is shipped by the shipping service:
+//
+//	Paid --ShipmentDispatched--> Shipped --ShipmentDelivered--> Delivered
+//
+// ShipmentCreated only records the tracking number, the order stays Paid and
+// can still be refunded until the carrier picks it up. Shipment events of an
+// order that is being or was refunded, or of one whose payment raced a
+// cancel, are accepted without changing the status; the shipment itself is
+// recorded either way.
+var transitions = map[Status]map[Event]Status{
+	StatusPending: {
+		EventReservationSucceeded: StatusAwaitingPayment,
+		EventReservationFailed:    StatusFailed,
+		EventExpired:              StatusCompensating,
+		EventCancelled:            StatusCancelling,
+	},
+	StatusAwaitingPayment: {
+		EventPaymentSucceeded: StatusPaid,
+		EventPaymentFailed:    StatusCompensating,
+		EventExpired:          StatusCompensating,
+		EventCancelled:        StatusCancelling,
+	},
+	StatusCompensating: {
+		EventReservationReleased: StatusFailed,
+		EventPaymentSucceeded:    StatusCompensating,
+		EventPaymentRefunded:     StatusCompensating,
+		EventTimedOut:            StatusCompensating,
+	},
+	StatusFailed: {
+		EventPaymentSucceeded: StatusFailed,
+		EventPaymentRefunded:  StatusFailed,
+	},
+	StatusCancelling: {
+		EventReservationReleased:  StatusCancelled,
+		EventReservationSucceeded: StatusCancelling,
+		EventPaymentSucceeded:     StatusCancelling,
+		EventPaymentRefunded:      StatusCancelling,
+		EventShipmentCreated:      StatusCancelling,
+		EventTimedOut:             StatusCancelling,
+	},
+	StatusCancelled: {
+		EventPaymentSucceeded: StatusCancelled,
+		EventPaymentRefunded:  StatusCancelled,
+		EventShipmentCreated:  StatusCancelled,
+	},
+	StatusPaid: {
+		EventRefundRequested:    StatusRefunding,
+		EventShipmentCreated:    StatusPaid,
+		EventShipmentDispatched: StatusShipped,
+		EventShipmentDelivered:  StatusDelivered,
+	},
+	StatusShipped: {
+		EventShipmentDelivered: StatusDelivered,
+	},
+	StatusRefunding: {
+		EventRefundSucceeded:        StatusRefunded,
+		EventPartialRefundSucceeded: StatusPaid,
+		EventRefundFailed:           StatusPaid,
+		EventShipmentCreated:        StatusRefunding,
+		EventShipmentDispatched:     StatusRefunding,
+		EventShipmentDelivered:      StatusRefunding,
+		EventTimedOut:               StatusRefunding,
+	},
+	StatusRefunded: {
+		EventShipmentCreated:    StatusRefunded,
+		EventShipmentDispatched: StatusRefunded,
+		EventShipmentDelivered:  StatusRefunded,
+	},
+}
+
+// customerEvents are the saga events only ever triggered by the customer.
+var customerEvents = map[Event]bool{
+	EventCancelled:       true,
+	EventRefundRequested: true,
+}
+
+var ErrIllegalTransition = errors.New("illegal order status transition")
+
+type ErrIllegalTransitionFrom struct {
+	From  Status
+	Event Event
+}
+
+func (e *ErrIllegalTransitionFrom) Error() string {
+	return fmt.Sprintf("illegal order status transition: %s on %s", e.Event, e.From)
+}
+
+func (e *ErrIllegalTransitionFrom) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+func NewErrIllegalTransition(from Status, evt Event) error {
+	return &ErrIllegalTransitionFrom{From: from, Event: evt}
+}
+
+// Next returns the status the saga moves to when evt arrives in status s.
+func (s Status) Next(evt Event) (Status, error) {
+	next, ok := transitions[s][evt]
+	if !ok {
+		return s, NewErrIllegalTransition(s, evt)
+	}
+	return next, nil
+}
+
+// IsTerminal reports whether no saga event can move the order out of status s.
+func (s Status) IsTerminal() bool {
+	for _, next := range transitions[s] {
+		if next != s {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSettled reports whether the order stays in status s unless the customer
+// acts on it, so nobody watching it has to wait for the saga any longer.
+func (s Status) IsSettled() bool {
+	for evt, next := range transitions[s] {
+		if next != s && !customerEvents[evt] {
+			return false
+		}
+	}
+	return true
+}
+
+// SagaStep is one entry of the per-order saga log. Rejected events are
+// recorded too, with Accepted set to false and the reason filled in.
+type SagaStep struct {
+	ID         int64     `json:"id"`
+	OrderID    string    `json:"order_id"`
+	Event      Event     `json:"event"`
+	FromStatus Status    `json:"from_status"`
+	ToStatus   Status    `json:"to_status"`
+	Accepted   bool      `json:"accepted"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Apply feeds evt into the order's saga. The order is only modified when the
+// transition is legal; the returned step describes the outcome either way.
+// An order leaving Refunding for Paid needs its Shipment loaded.
+func (o *Order) Apply(evt Event) (SagaStep, error) {
+	now := time.Now()
+	step := SagaStep{
+		OrderID:    o.ID,
+		Event:      evt,
+		FromStatus: o.Status,
+		ToStatus:   o.Status,
+		CreatedAt:  now,
+	}
+
+	next, err := o.Status.Next(evt)
+	if err != nil {
+		step.Reason = err.Error()
+		return step, err
+	}
+	if o.Status == StatusRefunding && next == StatusPaid {
+		// the shipment may have moved on while the refund was open
+		next = o.Shipment.orderStatus()
+	}
+
+	o.Status = next
+	o.UpdatedAt = now
+	step.ToStatus = next
+	step.Accepted = true
+	return step, nil
+}
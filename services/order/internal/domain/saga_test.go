@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestApplyRefundAfterShipment(t *testing.T) {
+	tests := []struct {
+		name     string
+		shipment *Shipment
+		evt      Event
+		want     Status
+	}{
+		{"partial refund, not shipped", nil, EventPartialRefundSucceeded, StatusPaid},
+		{"failed refund, shipment booked", &Shipment{Status: ShipmentCreated}, EventRefundFailed, StatusPaid},
+		// the carrier picked the order up while the refund was open
+		{"partial refund, dispatched", &Shipment{Status: ShipmentDispatched}, EventPartialRefundSucceeded, StatusShipped},
+		{"failed refund, delivered", &Shipment{Status: ShipmentDelivered}, EventRefundFailed, StatusDelivered},
+		{"full refund, delivered", &Shipment{Status: ShipmentDelivered}, EventRefundSucceeded, StatusRefunded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{Status: StatusRefunding, Shipment: tt.shipment}
+			if _, err := o.Apply(tt.evt); err != nil {
+				t.Fatalf("Apply(%s) = %v", tt.evt, err)
+			}
+			if o.Status != tt.want {
+				t.Errorf("Apply(%s) moved to %s, want %s", tt.evt, o.Status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type ShipmentStatus string
+
+const (
+	ShipmentCreated    ShipmentStatus = "Created"
+	ShipmentDispatched ShipmentStatus = "Dispatched"
+	ShipmentDelivered  ShipmentStatus = "Delivered"
+)
+
+// Shipment is what the shipping service reported about the delivery of an
+// order. TrackingURL points at the carrier's tracking page.
+type Shipment struct {
+	ID             string         `json:"id"`
+	OrderID        string         `json:"order_id"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	TrackingURL    string         `json:"tracking_url,omitempty"`
+	Status         ShipmentStatus `json:"status"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// orderStatus returns the status of a paid order whose shipment got as far as
+// s. Without a shipment, or before the carrier picked it up, the order is Paid.
+func (s *Shipment) orderStatus() Status {
+	if s == nil {
+		return StatusPaid
+	}
+	switch s.Status {
+	case ShipmentDispatched:
+		return StatusShipped
+	case ShipmentDelivered:
+		return StatusDelivered
+	default:
+		return StatusPaid
+	}
+}
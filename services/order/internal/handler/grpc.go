@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+	"github.com/axmz/go-saga-microservices/services/order/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// GRPCServer serves the OrderService gRPC API on top of the same service as
+// the HTTP handlers.
+type GRPCServer struct {
+	rpc.UnimplementedOrderServiceServer
+	Service *service.Service
+}
+
+func NewGRPCServer(service *service.Service) *GRPCServer {
+	return &GRPCServer{
+		Service: service,
+	}
+}
+
+// CreateOrder places the order like POST /orders. The "idempotency-key" and
+// "prefer" metadata stand for the headers of the same name; an order still in
+// progress is returned in status Pending instead of with 202 Accepted.
+func (s *GRPCServer) CreateOrder(ctx context.Context, req *httppb.CreateOrderRequest) (*httppb.CreateOrderResponse, error) {
+	customer, domainItems, err := validateCreateOrderRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	async := prefersAsync(md.Get("prefer"))
+
+	var key string
+	if keys := md.Get(strings.ToLower(idempotencyKeyHeader)); len(keys) > 0 {
+		key = keys[0]
+	}
+	idem, err := newIdempotency(key, req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if idem == nil {
+		_, response, _, err := s.createOrder(ctx, customer, domainItems, nil, async)
+		return response, err
+	}
+
+	rec, err := s.Service.LookupIdempotencyRecord(ctx, idem)
+	switch {
+	case err != nil:
+		return nil, statusError(err, "failed to look up idempotency key", "key", idem.Key)
+	case rec != nil:
+		return s.replayIdempotent(ctx, idem.Key, rec.Response, rec.OrderID)
+	}
+
+	order, response, statusCode, err := s.createOrder(ctx, customer, domainItems, idem, async)
+	var claimed *domain.ErrIdempotencyKeyClaimedBy
+	if errors.As(err, &claimed) {
+		return s.replayIdempotent(ctx, idem.Key, nil, claimed.OrderID)
+	}
+	if order != nil {
+		// the order exists now, keep the answer even if the client went away
+		if err := s.Service.SaveIdempotentResponse(context.WithoutCancel(ctx), idem, storedCreateOrder(order, response, statusCode, err)); err != nil {
+			slog.Error("failed to save idempotent response", "key", idem.Key, "orderID", order.ID, "err", err)
+		}
+	}
+	return response, err
+}
+
+// createOrder returns the order when it was stored, with the HTTP status code
+// POST /orders would have answered with.
+func (s *GRPCServer) createOrder(ctx context.Context, customer domain.Customer, domainItems []domain.Item, idem *domain.Idempotency, async bool) (*domain.Order, *httppb.CreateOrderResponse, int, error) {
+	var (
+		order *domain.Order
+		err   error
+	)
+	if async {
+		order, err = s.Service.PlaceOrder(ctx, customer, domainItems, idem)
+	} else {
+		order, err = s.Service.CreateOrder(ctx, customer, domainItems, idem)
+	}
+
+	switch {
+	case err == nil && async:
+		return order, &httppb.CreateOrderResponse{Order: toProtoOrder(order)}, http.StatusAccepted, nil
+	case err == nil:
+		return order, &httppb.CreateOrderResponse{Order: toProtoOrder(order)}, http.StatusCreated, nil
+	case errors.Is(err, domain.ErrOrderPending):
+		return order, &httppb.CreateOrderResponse{Order: toProtoOrder(order)}, http.StatusAccepted, nil
+	case errors.Is(err, domain.ErrIdempotencyKeyClaimed):
+		return nil, nil, 0, err
+	default:
+		return order, nil, http.StatusInternalServerError, statusError(err, "failed to create order")
+	}
+}
+
+// storedCreateOrder keeps the answer of a gRPC call the way the HTTP handler
+// keeps its response, so that a key can be replayed over either API.
+func storedCreateOrder(order *domain.Order, response *httppb.CreateOrderResponse, statusCode int, err error) domain.IdempotentResponse {
+	if err != nil {
+		return domain.IdempotentResponse{
+			StatusCode: statusCode,
+			Headers:    map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+			Body:       []byte(status.Convert(err).Message() + "\n"),
+		}
+	}
+
+	body, err := proto.Marshal(response)
+	if err != nil {
+		slog.Error("Marshal proto response failed", "err", err)
+	}
+	headers := map[string]string{"Content-Type": "application/x-protobuf"}
+	if statusCode == http.StatusAccepted {
+		headers["Location"] = "/orders/" + order.ID
+	}
+	return domain.IdempotentResponse{StatusCode: statusCode, Headers: headers, Body: body}
+}
+
+// replayIdempotent answers a call whose key was claimed before, like the HTTP
+// handler's replayIdempotent.
+func (s *GRPCServer) replayIdempotent(ctx context.Context, key string, stored *domain.IdempotentResponse, orderID string) (*httppb.CreateOrderResponse, error) {
+	resp, err := idempotentResponse(ctx, s.Service, stored, orderID)
+	if err != nil {
+		return nil, statusError(err, "failed to rebuild idempotent response", "key", key, "orderID", orderID)
+	}
+	slog.Info("Replaying idempotent response", "key", key, "status", resp.StatusCode)
+	return replayCreateOrder(ctx, resp)
+}
+
+// replayCreateOrder answers with a response stored by an earlier request,
+// made over gRPC or HTTP.
+func replayCreateOrder(ctx context.Context, stored *domain.IdempotentResponse) (*httppb.CreateOrderResponse, error) {
+	if err := grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true")); err != nil {
+		slog.Warn("Set replay header failed", "err", err)
+	}
+
+	switch stored.StatusCode {
+	case http.StatusCreated, http.StatusAccepted:
+		var response httppb.CreateOrderResponse
+		if err := proto.Unmarshal(stored.Body, &response); err != nil {
+			slog.Error("failed to unmarshal stored response", "err", err)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		return &response, nil
+	case http.StatusBadRequest:
+		return nil, status.Error(codes.InvalidArgument, strings.TrimSpace(string(stored.Body)))
+	default:
+		return nil, status.Error(codes.Internal, strings.TrimSpace(string(stored.Body)))
+	}
+}
+
+func (s *GRPCServer) GetOrder(ctx context.Context, req *httppb.GetOrderRequest) (*httppb.GetOrderResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing order_id")
+	}
+
+	ord, err := s.Service.GetOrder(ctx, req.OrderId)
+	if err != nil {
+		return nil, statusError(err, "failed to get order", "orderID", req.OrderId)
+	}
+
+	return &httppb.GetOrderResponse{Order: toPublicProtoOrder(ord)}, nil
+}
+
+func (s *GRPCServer) ListOrders(ctx context.Context, req *httppb.ListOrdersRequest) (*httppb.ListOrdersResponse, error) {
+	filter, err := toOrderFilter(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	orders, next, err := s.Service.ListOrders(ctx, filter)
+	if err != nil {
+		return nil, statusError(err, "failed to list orders")
+	}
+
+	return toListOrdersResponse(orders, next), nil
+}
+
+func (s *GRPCServer) GetOrderHistory(ctx context.Context, req *httppb.GetOrderHistoryRequest) (*httppb.GetOrderHistoryResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing order_id")
+	}
+
+	changes, err := s.Service.GetOrderHistory(ctx, req.OrderId)
+	if err != nil {
+		return nil, statusError(err, "failed to get order history", "orderID", req.OrderId)
+	}
+
+	return toGetOrderHistoryResponse(changes), nil
+}
+
+// CancelOrder answers a rejected cancel with accepted set to false rather than
+// with an error, like the body of the 409 of POST /orders/{orderID}/cancel.
+func (s *GRPCServer) CancelOrder(ctx context.Context, req *httppb.CancelOrderRequest) (*httppb.CancelOrderResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing order_id")
+	}
+
+	customerID, err := validateCustomerID(req.CustomerId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	step, err := s.Service.CancelOrder(ctx, req.OrderId, customerID)
+	if err != nil && !errors.Is(err, domain.ErrIllegalTransition) {
+		return nil, statusError(err, "failed to cancel order", "orderID", req.OrderId)
+	}
+
+	return toCancelOrderResponse(step), nil
+}
+
+func (s *GRPCServer) RefundOrder(ctx context.Context, req *httppb.RefundOrderRequest) (*httppb.RefundOrderResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing order_id")
+	}
+
+	customerID, lines, reason, err := validateRefundOrderRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	refund, step, err := s.Service.RefundOrder(ctx, req.OrderId, customerID, lines, reason)
+	if err != nil {
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("order cannot be refunded in status %s", step.FromStatus))
+		}
+		return nil, statusError(err, "failed to refund order", "orderID", req.OrderId)
+	}
+
+	return &httppb.RefundOrderResponse{
+		Refund: toProtoRefund(refund),
+		Status: string(step.ToStatus),
+	}, nil
+}
+
+func (s *GRPCServer) ListRefunds(ctx context.Context, req *httppb.ListRefundsRequest) (*httppb.ListRefundsResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing order_id")
+	}
+
+	refunds, err := s.Service.ListRefunds(ctx, req.OrderId)
+	if err != nil {
+		return nil, statusError(err, "failed to list refunds", "orderID", req.OrderId)
+	}
+
+	return toListRefundsResponse(refunds), nil
+}
+
+// WatchOrderStatus streams the status of an order like the WebSocket and SSE
+// endpoints and ends once the order reaches a settled status.
+func (s *GRPCServer) WatchOrderStatus(req *httppb.WatchOrderStatusRequest, stream grpc.ServerStreamingServer[httppb.WatchOrderStatusResponse]) error {
+	if req.OrderId == "" {
+		return status.Error(codes.InvalidArgument, "missing order_id")
+	}
+
+	ctx := stream.Context()
+	updates, err := s.Service.FollowStatus(ctx, req.OrderId)
+	if err != nil {
+		return statusError(err, "failed to follow order status", "orderID", req.OrderId)
+	}
+
+	for u := range updates {
+		if err := stream.Send(&httppb.WatchOrderStatusResponse{Update: toProtoStatusUpdate(u)}); err != nil {
+			slog.Info("Order status stream closed", "orderID", req.OrderId, "err", err)
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// statusError maps an error of the service layer to the gRPC status matching
+// the HTTP status code the handlers answer with. Unexpected errors are logged
+// with msg and args and hidden from the client.
+func statusError(err error, msg string, args ...any) error {
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound), errors.Is(err, domain.ErrRefundNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrUnknownProduct), errors.Is(err, domain.ErrInvalidRefund):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrIllegalTransition), errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrConcurrentModification):
+		// the order kept changing under the call, the client may try again
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		slog.Error(msg, append(args, "err", err)...)
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
@@ -2,46 +2,111 @@ package handler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
 	"time"
 
 	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
 	"github.com/axmz/go-saga-microservices/pkg/proto/events"
 	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
 	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
 	"github.com/axmz/go-saga-microservices/services/order/internal/service"
+	"github.com/gorilla/websocket"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 )
 
 type Handler struct {
-	Service *service.Service
+	Service  *service.Service
+	Upgrader websocket.Upgrader
 }
 
-func New(service *service.Service) *Handler {
+// New creates a handler whose WebSocket streams accept connections from the
+// order service itself and from the storefront at storefrontHost.
+func New(service *service.Service, storefrontHost string) *Handler {
 	return &Handler{
-		Service: service,
+		Service:  service,
+		Upgrader: newUpgrader(storefrontHost),
 	}
 }
 
 func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
-	domainItems, err := h.processCreateOrderRequest(r)
+	customer, domainItems, idem, err := h.processCreateOrderRequest(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	order, err := h.Service.CreateOrder(r.Context(), domainItems)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if idem == nil {
+		h.createOrder(w, r, customer, domainItems, nil)
 		return
 	}
 
-	h.respondWithCreateOrderSuccess(w, order)
+	record, err := h.Service.LookupIdempotencyRecord(r.Context(), idem)
+	switch {
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		slog.Error("failed to look up idempotency key", "err", err)
+		return
+	case record != nil:
+		h.replayIdempotent(w, r, idem.Key, record.Response, record.OrderID)
+		return
+	}
+
+	rec := newResponseRecorder(w)
+	if order := h.createOrder(rec, r, customer, domainItems, idem); order != nil {
+		// the order exists now, keep the answer even if the client went away
+		if err := h.Service.SaveIdempotentResponse(context.WithoutCancel(r.Context()), idem, rec.response()); err != nil {
+			slog.Error("failed to save idempotent response", "key", idem.Key, "orderID", order.ID, "err", err)
+		}
+	}
+}
+
+// createOrder serves POST /orders and returns the order when it was stored.
+func (h *Handler) createOrder(w http.ResponseWriter, r *http.Request, customer domain.Customer, domainItems []domain.Item, idem *domain.Idempotency) *domain.Order {
+	var claimed *domain.ErrIdempotencyKeyClaimedBy
+	if prefersAsync(r.Header.Values("Prefer")) {
+		order, err := h.Service.PlaceOrder(r.Context(), customer, domainItems, idem)
+		if errors.As(err, &claimed) {
+			h.replayIdempotent(w, r, idem.Key, nil, claimed.OrderID)
+			return nil
+		}
+		if err != nil {
+			h.respondWithCreateOrderError(w, err)
+			return nil
+		}
+		h.respondWithCreateOrderAccepted(w, order)
+		return order
+	}
+
+	order, err := h.Service.CreateOrder(r.Context(), customer, domainItems, idem)
+	if errors.As(err, &claimed) {
+		h.replayIdempotent(w, r, idem.Key, nil, claimed.OrderID)
+		return nil
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderPending) {
+			h.respondWithCreateOrderAccepted(w, order)
+			return order
+		}
+		h.respondWithCreateOrderError(w, err)
+		return order
+	}
+
+	h.respondWithCreateOrderSuccess(w, order, http.StatusCreated)
+	return order
 }
 
 func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
@@ -69,151 +134,709 @@ func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	h.respondWithGetOrderSuccess(w, ord)
 }
 
-func (h *Handler) OrderStatusWS(w http.ResponseWriter, r *http.Request) {
-	orderID := r.URL.Query().Get("orderId")
+func (h *Handler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.processListOrdersRequest(r)
+	if err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+
+	orders, next, err := h.Service.ListOrders(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		slog.Error("failed to list orders", "err", err)
+		return
+	}
+
+	h.respondWithListOrdersSuccess(w, orders, next)
+}
+
+func (h *Handler) GetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue("orderID")
 	if orderID == "" {
 		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
 		return
 	}
-	if r.Header.Get("Connection") != "Upgrade" || r.Header.Get("Upgrade") != "websocket" {
-		httputils.ErrorBadRequest(w, errors.New("not a websocket upgrade request"))
+
+	changes, err := h.Service.GetOrderHistory(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			slog.Error("failed to get order history", "orderID", orderID, "err", err)
+		}
 		return
 	}
-	hj, ok := w.(http.Hijacker)
-	if !ok {
-		httputils.ErrorInternal(w, errors.New("webserver doesn't support hijacking"))
+
+	h.respondWithGetOrderHistorySuccess(w, changes)
+}
+
+func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue("orderID")
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
 		return
 	}
-	conn, bufrw, err := hj.Hijack()
+
+	customerID, err := h.processCancelOrderRequest(r)
 	if err != nil {
-		httputils.ErrorInternal(w, errors.New("failed to hijack connection"))
+		httputils.ErrorBadRequest(w, err)
 		return
 	}
-	defer conn.Close()
-	// Write WebSocket handshake response
-	bufrw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
-	bufrw.Flush()
-	for {
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		ord, err := h.Service.GetOrder(ctx, orderID)
-		cancel()
-		if err != nil {
-			bufrw.WriteString("error: " + err.Error() + "\n")
-			bufrw.Flush()
-			return
-		}
-		if ord == nil {
-			bufrw.WriteString("error: Order not found\n")
-			bufrw.Flush()
-			return
+
+	step, err := h.Service.CancelOrder(r.Context(), orderID, customerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrIllegalTransition):
+			h.respondWithCancelOrder(w, step, http.StatusConflict)
+		case errors.Is(err, domain.ErrOrderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrConcurrentModification):
+			// the order kept changing under the cancel, the client may try again
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			slog.Error("failed to cancel order", "orderID", orderID, "err", err)
 		}
-		bufrw.WriteString("status: " + string(ord.Status) + "\n")
-		bufrw.Flush()
-		if ord.Status == domain.StatusPaid || ord.Status == domain.StatusFailed {
-			return
+		return
+	}
+
+	h.respondWithCancelOrder(w, step, http.StatusOK)
+}
+
+func (h *Handler) RefundOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue("orderID")
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
+		return
+	}
+
+	customerID, lines, reason, err := h.processRefundOrderRequest(r)
+	if err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+
+	refund, step, err := h.Service.RefundOrder(r.Context(), orderID, customerID, lines, reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrIllegalTransition):
+			http.Error(w, fmt.Sprintf("order cannot be refunded in status %s", step.FromStatus), http.StatusConflict)
+		case errors.Is(err, domain.ErrInvalidRefund):
+			httputils.ErrorBadRequest(w, err)
+		case errors.Is(err, domain.ErrOrderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrConcurrentModification):
+			// the order kept changing under the refund, the client may try again
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			slog.Error("failed to refund order", "orderID", orderID, "err", err)
 		}
-		time.Sleep(2 * time.Second)
+		return
 	}
+
+	response := &httppb.RefundOrderResponse{
+		Refund: toProtoRefund(refund),
+		Status: string(step.ToStatus),
+	}
+	httputils.RespondProto(w, response, http.StatusAccepted)
 }
 
-func (h *Handler) InventoryEvents(ctx context.Context, m kafka.Message) {
-	var envelope events.InventoryEventEnvelope
-	if err := proto.Unmarshal(m.Value, &envelope); err != nil {
-		slog.Warn("failed to unmarshal InventoryEventEnvelope: ", "err", err)
+func (h *Handler) ListRefunds(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue("orderID")
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
 		return
 	}
 
+	refunds, err := h.Service.ListRefunds(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			slog.Error("failed to list refunds", "orderID", orderID, "err", err)
+		}
+		return
+	}
+
+	httputils.RespondProto(w, toListRefundsResponse(refunds), http.StatusOK)
+}
+
+func (h *Handler) InventoryEvents(ctx context.Context, m kafka.Message) error {
+	envelope, err := codec.Decode[*events.InventoryEventEnvelope](m)
+	if err != nil {
+		return err
+	}
+
 	slog.Info("Received inventory event", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
 	switch evt := envelope.Event.(type) {
 	case *events.InventoryEventEnvelope_ReservationSucceeded:
-		h.Service.UpdateOrderAwaitingPayment(ctx, evt.ReservationSucceeded.Id)
+		h.Service.HandleSagaEvent(ctx, evt.ReservationSucceeded.Id, domain.EventReservationSucceeded, causeOf(m, envelope.EventId))
 	case *events.InventoryEventEnvelope_ReservationFailed:
-		h.Service.UpdateOrderFailed(ctx, evt.ReservationFailed.Id)
+		slog.Info("Inventory reservation failed", "orderID", evt.ReservationFailed.Id, "reason", evt.ReservationFailed.Reason)
+		h.Service.HandleSagaEvent(ctx, evt.ReservationFailed.Id, domain.EventReservationFailed, causeOf(m, envelope.EventId))
+	case *events.InventoryEventEnvelope_ReservationReleased:
+		h.Service.HandleSagaEvent(ctx, evt.ReservationReleased.Id, domain.EventReservationReleased, causeOf(m, envelope.EventId))
+	case *events.InventoryEventEnvelope_ProductCreated,
+		*events.InventoryEventEnvelope_ProductUpdated,
+		*events.InventoryEventEnvelope_PriceChanged:
+		// orders are priced from GET /prices, catalog events need nothing from order
 	default:
 		slog.Warn("Unknown or missing event type in envelope")
 	}
+	return nil
 }
 
-func (h *Handler) PaymentEvents(ctx context.Context, m kafka.Message) {
-	var envelope events.PaymentEventEnvelope
-	if err := proto.Unmarshal(m.Value, &envelope); err != nil {
-		slog.Warn("Failed to unmarshal PaymentEventEnvelope:", "err", err)
-		return
+func (h *Handler) PaymentEvents(ctx context.Context, m kafka.Message) error {
+	envelope, err := codec.Decode[*events.PaymentEventEnvelope](m)
+	if err != nil {
+		return err
 	}
 
 	slog.Info("Received payment event", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
 	switch evt := envelope.Event.(type) {
 	case *events.PaymentEventEnvelope_PaymentSucceeded:
-		h.Service.UpdateOrderPaid(ctx, evt.PaymentSucceeded.Id)
+		h.Service.HandleSagaEvent(ctx, evt.PaymentSucceeded.Id, domain.EventPaymentSucceeded, causeOf(m, envelope.EventId))
 	case *events.PaymentEventEnvelope_PaymentFailed:
-		h.Service.UpdateOrderFailed(ctx, evt.PaymentFailed.Id)
+		h.Service.HandleSagaEvent(ctx, evt.PaymentFailed.Id, domain.EventPaymentFailed, causeOf(m, envelope.EventId))
+	case *events.PaymentEventEnvelope_PaymentRefunded:
+		h.Service.HandleSagaEvent(ctx, evt.PaymentRefunded.Id, domain.EventPaymentRefunded, causeOf(m, envelope.EventId))
+	case *events.PaymentEventEnvelope_RefundSucceeded:
+		h.Service.HandleRefundReply(ctx, evt.RefundSucceeded.Id, evt.RefundSucceeded.RefundId, true, "", causeOf(m, envelope.EventId))
+	case *events.PaymentEventEnvelope_RefundFailed:
+		h.Service.HandleRefundReply(ctx, evt.RefundFailed.Id, evt.RefundFailed.RefundId, false, evt.RefundFailed.Reason, causeOf(m, envelope.EventId))
 	default:
 		slog.Warn("Unknown or missing event type in envelope")
 	}
+	return nil
 }
 
-func (h *Handler) processCreateOrderRequest(r *http.Request) ([]domain.Item, error) {
+func (h *Handler) ShippingEvents(ctx context.Context, m kafka.Message) error {
+	envelope, err := codec.Decode[*events.ShippingEventEnvelope](m)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Received shipping event", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
+	switch evt := envelope.Event.(type) {
+	case *events.ShippingEventEnvelope_ShipmentCreated:
+		e := evt.ShipmentCreated
+		shipment := toDomainShipment(e.Id, e.ShipmentId, e.Carrier, e.TrackingNumber, domain.ShipmentCreated, e.CreatedAt)
+		shipment.TrackingURL = e.TrackingUrl
+		h.Service.HandleShipmentEvent(ctx, shipment, domain.EventShipmentCreated, causeOf(m, envelope.EventId))
+	case *events.ShippingEventEnvelope_ShipmentDispatched:
+		e := evt.ShipmentDispatched
+		shipment := toDomainShipment(e.Id, e.ShipmentId, e.Carrier, e.TrackingNumber, domain.ShipmentDispatched, e.DispatchedAt)
+		h.Service.HandleShipmentEvent(ctx, shipment, domain.EventShipmentDispatched, causeOf(m, envelope.EventId))
+	case *events.ShippingEventEnvelope_ShipmentDelivered:
+		e := evt.ShipmentDelivered
+		shipment := toDomainShipment(e.Id, e.ShipmentId, e.Carrier, e.TrackingNumber, domain.ShipmentDelivered, e.DeliveredAt)
+		h.Service.HandleShipmentEvent(ctx, shipment, domain.EventShipmentDelivered, causeOf(m, envelope.EventId))
+	default:
+		slog.Warn("Unknown or missing event type in envelope")
+	}
+	return nil
+}
+
+// toDomainShipment builds the shipment reported by a shipping event. A missing
+// or malformed event time counts as now.
+func toDomainShipment(orderID, shipmentID, carrier, trackingNumber string, status domain.ShipmentStatus, at string) domain.Shipment {
+	updatedAt, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		updatedAt = time.Now()
+	}
+	return domain.Shipment{
+		ID:             shipmentID,
+		OrderID:        orderID,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		Status:         status,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+// causeOf points the status history at the Kafka message that triggered a change.
+func causeOf(m kafka.Message, eventID string) domain.Cause {
+	return domain.Cause{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		EventID:   eventID,
+	}
+}
+
+// prefersAsync reports whether the given Prefer header values, or "prefer"
+// metadata over gRPC, ask for "respond-async" (RFC 7240).
+func prefersAsync(prefer []string) bool {
+	for _, v := range prefer {
+		for _, pref := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (h *Handler) processCreateOrderRequest(r *http.Request) (domain.Customer, []domain.Item, *domain.Idempotency, error) {
 	var req httppb.CreateOrderRequest
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		return nil, err
+		return domain.Customer{}, nil, nil, err
 	}
 
 	if err := proto.Unmarshal(body, &req); err != nil {
-		return nil, err
+		return domain.Customer{}, nil, nil, err
+	}
+
+	customer, domainItems, err := validateCreateOrderRequest(&req)
+	if err != nil {
+		return domain.Customer{}, nil, nil, err
+	}
+
+	idem, err := newIdempotency(r.Header.Get(idempotencyKeyHeader), &req)
+	if err != nil {
+		return domain.Customer{}, nil, nil, err
+	}
+
+	return customer, domainItems, idem, nil
+}
+
+// validateCreateOrderRequest checks the order request and turns it into the
+// customer and the unpriced items of the order.
+func validateCreateOrderRequest(req *httppb.CreateOrderRequest) (domain.Customer, []domain.Item, error) {
+	customer, err := validateCustomer(req)
+	if err != nil {
+		return domain.Customer{}, nil, err
 	}
 
 	if len(req.Items) == 0 {
-		return nil, fmt.Errorf("no items provided")
+		return domain.Customer{}, nil, fmt.Errorf("no items provided")
 	}
 
 	domainItems := make([]domain.Item, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
 	for i, item := range req.Items {
+		if item.ProductId == "" {
+			return domain.Customer{}, nil, fmt.Errorf("item %d has no product_id", i)
+		}
+		if seen[item.ProductId] {
+			return domain.Customer{}, nil, fmt.Errorf("duplicate product_id %s", item.ProductId)
+		}
+		seen[item.ProductId] = true
+
+		quantity := int(item.Quantity)
+		if quantity == 0 {
+			quantity = 1
+		}
+		if quantity < 0 {
+			return domain.Customer{}, nil, fmt.Errorf("invalid quantity %d for %s", item.Quantity, item.ProductId)
+		}
+
+		// unit_price_minor and currency from the client are ignored, the service
+		// prices the items itself
 		domainItems[i] = domain.Item{
 			ProductID: item.ProductId,
+			Quantity:  quantity,
 		}
 	}
 
-	return domainItems, nil
+	return customer, domainItems, nil
 }
 
-func (h *Handler) respondWithCreateOrderSuccess(w http.ResponseWriter, order *domain.Order) {
-	protoOrder := &httppb.Order{
-		Id:     order.ID,
-		Status: string(order.Status),
+// newIdempotency ties the request to its Idempotency-Key; without a key it
+// returns nil. The hash is taken over a deterministic encoding of the parsed
+// request rather than the bytes on the wire, so that the same order sent over
+// HTTP and over gRPC hashes the same.
+func newIdempotency(key string, req *httppb.CreateOrderRequest) (*domain.Idempotency, error) {
+	if key == "" {
+		return nil, nil
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		return nil, fmt.Errorf("%s longer than %d characters", idempotencyKeyHeader, maxIdempotencyKeyLen)
+	}
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
 	}
+	sum := sha256.Sum256(body)
+	return &domain.Idempotency{Key: key, RequestHash: hex.EncodeToString(sum[:])}, nil
+}
 
-	for _, item := range order.Items {
-		protoOrder.Items = append(protoOrder.Items, &httppb.OrderItem{
-			ProductId: item.ProductID,
-		})
+const (
+	maxCustomerIDLen = 64
+	maxEmailLen      = 254
+	maxAddressLen    = 200
+)
+
+// validateCustomerID checks the id of the customer a request is made for.
+func validateCustomerID(customerID string) (string, error) {
+	customerID = strings.TrimSpace(customerID)
+	if customerID == "" {
+		return "", errors.New("missing customer_id")
+	}
+	if len(customerID) > maxCustomerIDLen {
+		return "", fmt.Errorf("customer_id longer than %d characters", maxCustomerIDLen)
+	}
+	return customerID, nil
+}
+
+// validateCustomer checks the customer and shipping details of the request.
+func validateCustomer(req *httppb.CreateOrderRequest) (domain.Customer, error) {
+	customerID, err := validateCustomerID(req.CustomerId)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return domain.Customer{}, errors.New("missing email")
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > maxEmailLen {
+		return domain.Customer{}, fmt.Errorf("invalid email %q", req.Email)
+	}
+
+	a := req.GetShippingAddress()
+	if a == nil {
+		return domain.Customer{}, errors.New("missing shipping_address")
+	}
+	address := domain.Address{
+		Name:       strings.TrimSpace(a.Name),
+		Line1:      strings.TrimSpace(a.Line1),
+		Line2:      strings.TrimSpace(a.Line2),
+		City:       strings.TrimSpace(a.City),
+		Region:     strings.TrimSpace(a.Region),
+		PostalCode: strings.TrimSpace(a.PostalCode),
+		Country:    strings.ToUpper(strings.TrimSpace(a.Country)),
+	}
+	for _, required := range []struct{ field, value string }{
+		{"name", address.Name},
+		{"line1", address.Line1},
+		{"city", address.City},
+		{"postal_code", address.PostalCode},
+	} {
+		if required.value == "" {
+			return domain.Customer{}, fmt.Errorf("missing shipping_address.%s", required.field)
+		}
+	}
+	for _, value := range []string{address.Name, address.Line1, address.Line2, address.City, address.Region, address.PostalCode} {
+		if len(value) > maxAddressLen {
+			return domain.Customer{}, fmt.Errorf("shipping_address field longer than %d characters", maxAddressLen)
+		}
+	}
+	if !isCountryCode(address.Country) {
+		return domain.Customer{}, fmt.Errorf("invalid shipping_address.country %q, want an ISO 3166-1 alpha-2 code", a.Country)
+	}
+
+	return domain.Customer{ID: customerID, Email: email, ShippingAddress: address}, nil
+}
+
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
 	}
+	return true
+}
+
+const maxRefundReasonLen = 500
+
+// processCancelOrderRequest reads the customer the order is cancelled for.
+func (h *Handler) processCancelOrderRequest(r *http.Request) (string, error) {
+	var req httppb.CancelOrderRequest
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	if err := proto.Unmarshal(body, &req); err != nil {
+		return "", err
+	}
+	return validateCustomerID(req.CustomerId)
+}
+
+// processRefundOrderRequest reads the customer the order is refunded for and
+// the lines to refund; without lines everything that is left is refunded.
+func (h *Handler) processRefundOrderRequest(r *http.Request) (string, []domain.RefundLine, string, error) {
+	var req httppb.RefundOrderRequest
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if err := proto.Unmarshal(body, &req); err != nil {
+		return "", nil, "", err
+	}
+	return validateRefundOrderRequest(&req)
+}
+
+func validateRefundOrderRequest(req *httppb.RefundOrderRequest) (string, []domain.RefundLine, string, error) {
+	customerID, err := validateCustomerID(req.CustomerId)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > maxRefundReasonLen {
+		return "", nil, "", fmt.Errorf("reason longer than %d characters", maxRefundReasonLen)
+	}
+
+	lines := make([]domain.RefundLine, len(req.Items))
+	for i, item := range req.Items {
+		if item.ProductId == "" {
+			return "", nil, "", fmt.Errorf("item %d has no product_id", i)
+		}
+		if item.Quantity < 0 {
+			return "", nil, "", fmt.Errorf("invalid quantity %d for %s", item.Quantity, item.ProductId)
+		}
+		// amounts from the client are ignored, refunds are priced like the order
+		lines[i] = domain.RefundLine{
+			ProductID: item.ProductId,
+			Quantity:  int(item.Quantity),
+		}
+	}
+	return customerID, lines, reason, nil
+}
+
+func (h *Handler) processListOrdersRequest(r *http.Request) (domain.OrderFilter, error) {
+	q := r.URL.Query()
+	req := &httppb.ListOrdersRequest{
+		Status:      q.Get("status"),
+		CreatedFrom: q.Get("created_from"),
+		CreatedTo:   q.Get("created_to"),
+		Sku:         q.Get("sku"),
+		Cursor:      q.Get("cursor"),
+		CustomerId:  q.Get("customer_id"),
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return domain.OrderFilter{}, fmt.Errorf("invalid limit: %s", v)
+		}
+		req.Limit = int32(limit)
+	}
+
+	return toOrderFilter(req)
+}
+
+// toOrderFilter checks a list request, taken from the query string or from
+// gRPC, and turns it into the filter of the orders to list.
+func toOrderFilter(req *httppb.ListOrdersRequest) (domain.OrderFilter, error) {
+	f := domain.OrderFilter{
+		Status:     domain.Status(req.Status),
+		SKU:        req.Sku,
+		CustomerID: req.CustomerId,
+		Limit:      int(req.Limit),
+	}
+	if f.Limit < 0 {
+		return f, fmt.Errorf("invalid limit: %d", req.Limit)
+	}
+
+	var err error
+	if req.CreatedFrom != "" {
+		if f.CreatedFrom, err = time.Parse(time.RFC3339, req.CreatedFrom); err != nil {
+			return f, fmt.Errorf("invalid created_from: %w", err)
+		}
+	}
+	if req.CreatedTo != "" {
+		if f.CreatedTo, err = time.Parse(time.RFC3339, req.CreatedTo); err != nil {
+			return f, fmt.Errorf("invalid created_to: %w", err)
+		}
+	}
+	if req.Cursor != "" {
+		if f.After, err = domain.DecodeCursor(req.Cursor); err != nil {
+			return f, err
+		}
+	}
+
+	return f, nil
+}
+
+func (h *Handler) respondWithCreateOrderError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, domain.ErrUnknownProduct) {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func (h *Handler) respondWithCreateOrderSuccess(w http.ResponseWriter, order *domain.Order, statusCode int) {
 	response := &httppb.CreateOrderResponse{
-		Order: protoOrder,
+		Order: toProtoOrder(order),
 	}
 
-	httputils.RespondProto(w, response, http.StatusCreated)
+	httputils.RespondProto(w, response, statusCode)
+}
+
+// respondWithCreateOrderAccepted tells the client the order exists but is still
+// in progress and where to follow it.
+func (h *Handler) respondWithCreateOrderAccepted(w http.ResponseWriter, order *domain.Order) {
+	w.Header().Set("Location", "/orders/"+order.ID)
+	h.respondWithCreateOrderSuccess(w, order, http.StatusAccepted)
 }
 
 func (h *Handler) respondWithGetOrderSuccess(w http.ResponseWriter, order *domain.Order) {
+	response := &httppb.GetOrderResponse{
+		Order: toPublicProtoOrder(order),
+	}
+
+	httputils.RespondProto(w, response, http.StatusOK)
+}
+
+func (h *Handler) respondWithListOrdersSuccess(w http.ResponseWriter, orders []domain.Order, next string) {
+	httputils.RespondProto(w, toListOrdersResponse(orders, next), http.StatusOK)
+}
+
+func (h *Handler) respondWithGetOrderHistorySuccess(w http.ResponseWriter, changes []domain.StatusChange) {
+	httputils.RespondProto(w, toGetOrderHistoryResponse(changes), http.StatusOK)
+}
+
+func (h *Handler) respondWithCancelOrder(w http.ResponseWriter, step domain.SagaStep, statusCode int) {
+	httputils.RespondProto(w, toCancelOrderResponse(step), statusCode)
+}
+
+func toListOrdersResponse(orders []domain.Order, next string) *httppb.ListOrdersResponse {
+	response := &httppb.ListOrdersResponse{
+		Orders:     make([]*httppb.Order, 0, len(orders)),
+		NextCursor: next,
+	}
+	for i := range orders {
+		response.Orders = append(response.Orders, toPublicProtoOrder(&orders[i]))
+	}
+	return response
+}
+
+func toGetOrderHistoryResponse(changes []domain.StatusChange) *httppb.GetOrderHistoryResponse {
+	response := &httppb.GetOrderHistoryResponse{
+		Changes: make([]*httppb.OrderStatusChange, 0, len(changes)),
+	}
+
+	for _, c := range changes {
+		response.Changes = append(response.Changes, &httppb.OrderStatusChange{
+			FromStatus: string(c.FromStatus),
+			ToStatus:   string(c.ToStatus),
+			Event:      string(c.Event),
+			Topic:      c.Cause.Topic,
+			Partition:  int32(c.Cause.Partition),
+			Offset:     c.Cause.Offset,
+			OutboxId:   c.Cause.OutboxID,
+			CreatedAt:  c.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return response
+}
+
+func toCancelOrderResponse(step domain.SagaStep) *httppb.CancelOrderResponse {
+	response := &httppb.CancelOrderResponse{
+		Accepted: step.Accepted,
+		Status:   string(step.ToStatus),
+	}
+	if !step.Accepted {
+		response.Reason = fmt.Sprintf("order cannot be cancelled in status %s", step.FromStatus)
+	}
+	return response
+}
+
+func toListRefundsResponse(refunds []domain.Refund) *httppb.ListRefundsResponse {
+	response := &httppb.ListRefundsResponse{
+		Refunds: make([]*httppb.Refund, 0, len(refunds)),
+	}
+	for i := range refunds {
+		response.Refunds = append(response.Refunds, toProtoRefund(&refunds[i]))
+	}
+	return response
+}
+
+// toPublicProtoOrder is toProtoOrder without the customer's email and
+// shipping address. Orders are read without authentication, so only the
+// answer to the request that placed the order carries them.
+func toPublicProtoOrder(order *domain.Order) *httppb.Order {
+	protoOrder := toProtoOrder(order)
+	protoOrder.Email = ""
+	protoOrder.ShippingAddress = nil
+	return protoOrder
+}
+
+func toProtoOrder(order *domain.Order) *httppb.Order {
 	protoOrder := &httppb.Order{
-		Id:        order.ID,
-		Status:    string(order.Status),
-		CreatedAt: order.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: order.UpdatedAt.Format(time.RFC3339),
+		Id:            order.ID,
+		Status:        string(order.Status),
+		CreatedAt:     order.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     order.UpdatedAt.Format(time.RFC3339),
+		SubtotalMinor: order.Subtotal,
+		TotalMinor:    order.Total,
+		Currency:      order.Currency,
+		CustomerId:    order.Customer.ID,
+		Email:         order.Customer.Email,
+		ShippingAddress: &httppb.Address{
+			Name:       order.Customer.ShippingAddress.Name,
+			Line1:      order.Customer.ShippingAddress.Line1,
+			Line2:      order.Customer.ShippingAddress.Line2,
+			City:       order.Customer.ShippingAddress.City,
+			Region:     order.Customer.ShippingAddress.Region,
+			PostalCode: order.Customer.ShippingAddress.PostalCode,
+			Country:    order.Customer.ShippingAddress.Country,
+		},
 	}
 
 	for _, item := range order.Items {
 		protoOrder.Items = append(protoOrder.Items, &httppb.OrderItem{
-			ProductId: item.ProductID,
+			ProductId:        item.ProductID,
+			Quantity:         int32(item.Quantity),
+			UnitPriceMinor:   item.UnitPrice,
+			Currency:         item.Currency,
+			RefundedQuantity: int32(item.RefundedQuantity),
 		})
 	}
 
-	response := &httppb.GetOrderResponse{
-		Order: protoOrder,
+	if s := order.Shipment; s != nil {
+		protoOrder.Shipment = &httppb.Shipment{
+			Id:             s.ID,
+			Carrier:        s.Carrier,
+			TrackingNumber: s.TrackingNumber,
+			TrackingUrl:    s.TrackingURL,
+			Status:         string(s.Status),
+			UpdatedAt:      s.UpdatedAt.Format(time.RFC3339),
+		}
 	}
 
-	httputils.RespondProto(w, response, http.StatusOK)
+	return protoOrder
+}
+
+func toProtoRefund(refund *domain.Refund) *httppb.Refund {
+	protoRefund := &httppb.Refund{
+		Id:            refund.ID,
+		OrderId:       refund.OrderID,
+		Status:        string(refund.Status),
+		AmountMinor:   refund.Amount,
+		Currency:      refund.Currency,
+		Full:          refund.Full,
+		Reason:        refund.Reason,
+		FailureReason: refund.FailureReason,
+		CreatedAt:     refund.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     refund.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, line := range refund.Lines {
+		protoRefund.Items = append(protoRefund.Items, &httppb.RefundItem{
+			ProductId:   line.ProductID,
+			Quantity:    int32(line.Quantity),
+			AmountMinor: line.Amount,
+		})
+	}
+	return protoRefund
 }
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+	"github.com/axmz/go-saga-microservices/services/order/internal/service"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
+// replayedHeaders are the response headers kept for replays.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// responseRecorder passes the response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	rr.statusCode = statusCode
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) response() domain.IdempotentResponse {
+	headers := make(map[string]string, len(replayedHeaders))
+	for _, name := range replayedHeaders {
+		if v := rr.Header().Get(name); v != "" {
+			headers[name] = v
+		}
+	}
+	return domain.IdempotentResponse{
+		StatusCode: rr.statusCode,
+		Headers:    headers,
+		Body:       rr.body.Bytes(),
+	}
+}
+
+func replayResponse(w http.ResponseWriter, resp *domain.IdempotentResponse) {
+	for name, v := range resp.Headers {
+		w.Header().Set(name, v)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(resp.StatusCode)
+	if _, err := w.Write(resp.Body); err != nil {
+		slog.Warn("Write replayed response failed", "err", err)
+	}
+}
+
+// replayIdempotent answers a request whose key was claimed before with the
+// response stored by the first request, or with one rebuilt from the order it
+// created when it has not stored one yet.
+func (h *Handler) replayIdempotent(w http.ResponseWriter, r *http.Request, key string, stored *domain.IdempotentResponse, orderID string) {
+	resp, err := idempotentResponse(r.Context(), h.Service, stored, orderID)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		slog.Error("failed to rebuild idempotent response", "key", key, "orderID", orderID, "err", err)
+		return
+	}
+	slog.Info("Replaying idempotent response", "key", key, "status", resp.StatusCode)
+	replayResponse(w, resp)
+}
+
+// idempotentResponse returns stored, or when it is nil, the response POST
+// /orders gives for the order as it is now: 202 Accepted while it is Pending,
+// 201 Created after. The first request of a key may still be waiting for the
+// saga, or may have died before it stored its response.
+func idempotentResponse(ctx context.Context, svc *service.Service, stored *domain.IdempotentResponse, orderID string) (*domain.IdempotentResponse, error) {
+	if stored != nil {
+		return stored, nil
+	}
+	order, err := svc.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	statusCode := http.StatusCreated
+	if order.Status == domain.StatusPending {
+		statusCode = http.StatusAccepted
+	}
+	resp := storedCreateOrder(order, &httppb.CreateOrderResponse{Order: toProtoOrder(order)}, statusCode, nil)
+	return &resp, nil
+}
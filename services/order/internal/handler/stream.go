@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+	"github.com/axmz/go-saga-microservices/services/order/internal/watcher"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// heartbeatInterval is how often an idle stream is pinged so that proxies
+	// keep it open and dead clients are noticed.
+	heartbeatInterval = 15 * time.Second
+	// writeWait bounds a single write to a stream.
+	writeWait = 10 * time.Second
+)
+
+// newUpgrader returns an upgrader that accepts connections from pages of the
+// order service or of the storefront only: the origin must be the host the
+// request was sent to or the configured storefront host. Requests without an
+// Origin header do not come from a browser and are let through.
+func newUpgrader(storefrontHost string) websocket.Upgrader {
+	return websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			u, err := url.Parse(origin)
+			if err != nil {
+				return false
+			}
+			return u.Host == r.Host || u.Hostname() == storefrontHost
+		},
+	}
+}
+
+// OrderStatusWS streams the status of an order over a WebSocket as JSON
+// OrderStatusUpdate messages. The connection is closed normally once the order
+// reaches a settled status.
+func (h *Handler) OrderStatusWS(w http.ResponseWriter, r *http.Request) {
+	orderID := r.URL.Query().Get("orderId")
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
+		return
+	}
+	if !websocket.IsWebSocketUpgrade(r) {
+		httputils.ErrorBadRequest(w, errors.New("not a websocket upgrade request"))
+		return
+	}
+
+	updates, ok := h.followStatus(w, r, orderID)
+	if !ok {
+		return
+	}
+
+	conn, err := h.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied to the client
+		slog.Warn("WebSocket upgrade failed:", "orderID", orderID, "err", err)
+		return
+	}
+	defer conn.Close()
+
+	// the client is not expected to send anything, but its control frames
+	// have to be read to answer pings and to notice it going away
+	closed := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		case u, ok := <-updates:
+			if !ok {
+				// the stream ended without a settled status, e.g. on shutdown
+				msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
+				_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+				return
+			}
+			b, err := marshalStatusUpdate(u)
+			if err != nil {
+				slog.Error("Failed to marshal order status update:", "orderID", orderID, "err", err)
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.TextMessage, b); err != nil {
+				return
+			}
+			if u.Status.IsSettled() {
+				msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, string(u.Status))
+				_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+				// give the client a moment to answer the close frame
+				select {
+				case <-closed:
+				case <-time.After(writeWait):
+				}
+				return
+			}
+		}
+	}
+}
+
+// OrderStatusEvents streams the status of an order as Server-Sent Events. Each
+// update is a "status" event carrying a JSON OrderStatusUpdate; a final "close"
+// event tells the client not to reconnect once the order is settled.
+func (h *Handler) OrderStatusEvents(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue("orderID")
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	updates, ok := h.followStatus(w, r, orderID)
+	if !ok {
+		return
+	}
+
+	// the server write timeout is meant for ordinary requests, not for streams
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		slog.Warn("Failed to clear write deadline for event stream:", "orderID", orderID, "err", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		slog.Warn("Event stream not supported:", "orderID", orderID, "err", err)
+		return
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+			b, err := marshalStatusUpdate(u)
+			if err != nil {
+				slog.Error("Failed to marshal order status update:", "orderID", orderID, "err", err)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", b); err != nil {
+				return
+			}
+			if u.Status.IsSettled() {
+				fmt.Fprint(w, "event: close\ndata: {}\n\n")
+				_ = rc.Flush()
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// followStatus subscribes to the status of the order, replying with an error
+// when that is not possible.
+func (h *Handler) followStatus(w http.ResponseWriter, r *http.Request, orderID string) (<-chan watcher.Update, bool) {
+	updates, err := h.Service.FollowStatus(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			httputils.ErrorNotFound(w, err)
+		} else {
+			httputils.ErrorInternal(w, err)
+			slog.Error("failed to follow order status", "orderID", orderID, "err", err)
+		}
+		return nil, false
+	}
+	return updates, true
+}
+
+func marshalStatusUpdate(u watcher.Update) ([]byte, error) {
+	return protojson.Marshal(toProtoStatusUpdate(u))
+}
+
+func toProtoStatusUpdate(u watcher.Update) *httppb.OrderStatusUpdate {
+	ts := u.UpdatedAt
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	return &httppb.OrderStatusUpdate{
+		OrderId:   u.OrderID,
+		Status:    string(u.Status),
+		Timestamp: ts.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+)
+
+func (r *Repository) InsertStatusChangeTx(ctx context.Context, tx *sql.Tx, c domain.StatusChange) error {
+	var topic sql.NullString
+	var partition, offset sql.NullInt64
+	if c.Cause.Topic != "" {
+		topic = sql.NullString{String: c.Cause.Topic, Valid: true}
+		partition = sql.NullInt64{Int64: int64(c.Cause.Partition), Valid: true}
+		offset = sql.NullInt64{Int64: c.Cause.Offset, Valid: true}
+	}
+	outboxID := sql.NullString{String: c.Cause.OutboxID, Valid: c.Cause.OutboxID != ""}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO order_status_history (order_id, from_status, to_status, event, kafka_topic, kafka_partition, kafka_offset, outbox_id, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		c.OrderID, c.FromStatus, c.ToStatus, c.Event, topic, partition, offset, outboxID, c.CreatedAt,
+	)
+	return err
+}
+
+// GetStatusHistory returns the status changes of the order, oldest first.
+func (r *Repository) GetStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	rows, err := r.DB.GetConn().QueryContext(ctx, `
+		SELECT id, order_id, from_status, to_status, event, kafka_topic, kafka_partition, kafka_offset, outbox_id, created_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("query status history of %s: %w", orderID, err)
+	}
+	defer rows.Close()
+
+	changes := make([]domain.StatusChange, 0)
+	for rows.Next() {
+		var c domain.StatusChange
+		var topic, outboxID sql.NullString
+		var partition, offset sql.NullInt64
+		if err := rows.Scan(&c.ID, &c.OrderID, &c.FromStatus, &c.ToStatus, &c.Event, &topic, &partition, &offset, &outboxID, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan status change: %w", err)
+		}
+		c.Cause = domain.Cause{
+			Topic:     topic.String,
+			Partition: int(partition.Int64),
+			Offset:    offset.Int64,
+			OutboxID:  outboxID.String,
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+)
+
+// ClaimIdempotencyKeyTx reserves the key for the order created in tx. A key
+// that is still within retention cannot be claimed twice: claiming it again
+// fails with domain.ErrIdempotencyKeyReused for a different request and with
+// domain.ErrIdempotencyKeyClaimed, naming the order of the first claim, for
+// the same one.
+func (r *Repository) ClaimIdempotencyKeyTx(ctx context.Context, tx *sql.Tx, idem *domain.Idempotency, orderID string) error {
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (key, request_hash, order_id, created_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			order_id = EXCLUDED.order_id,
+			status_code = NULL,
+			headers = NULL,
+			body = NULL,
+			created_at = EXCLUDED.created_at
+		WHERE idempotency_keys.created_at < $4
+	`, idem.Key, idem.RequestHash, orderID, idem.ExpiredBefore)
+	if err != nil {
+		return fmt.Errorf("claim idempotency key: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	// the conflicting claim has committed and its row is locked by the
+	// insert above
+	var hash, claimedBy string
+	if err := tx.QueryRowContext(ctx, `
+		SELECT request_hash, order_id FROM idempotency_keys WHERE key = $1
+	`, idem.Key).Scan(&hash, &claimedBy); err != nil {
+		return fmt.Errorf("read claimed idempotency key: %w", err)
+	}
+	if hash != idem.RequestHash {
+		return domain.ErrIdempotencyKeyReused
+	}
+	return domain.NewErrIdempotencyKeyClaimed(claimedBy)
+}
+
+// GetIdempotencyRecord returns the record of a key claimed at or after
+// expiredBefore, or nil when there is none.
+func (r *Repository) GetIdempotencyRecord(ctx context.Context, key string, expiredBefore time.Time) (*domain.IdempotencyRecord, error) {
+	var rec domain.IdempotencyRecord
+	var statusCode sql.NullInt64
+	var headers, body []byte
+	err := r.DB.GetConn().QueryRowContext(ctx, `
+		SELECT key, request_hash, order_id, status_code, headers, body, created_at
+		FROM idempotency_keys
+		WHERE key = $1 AND created_at >= $2
+	`, key, expiredBefore).Scan(&rec.Key, &rec.RequestHash, &rec.OrderID, &statusCode, &headers, &body, &rec.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query idempotency key: %w", err)
+	}
+
+	if statusCode.Valid {
+		rec.Response = &domain.IdempotentResponse{
+			StatusCode: int(statusCode.Int64),
+			Body:       body,
+		}
+		if err := json.Unmarshal(headers, &rec.Response.Headers); err != nil {
+			return nil, fmt.Errorf("unmarshal idempotent response headers: %w", err)
+		}
+	}
+	return &rec, nil
+}
+
+// SaveIdempotentResponse stores the response of the request that claimed the
+// key. A response stored before is kept.
+func (r *Repository) SaveIdempotentResponse(ctx context.Context, key string, resp domain.IdempotentResponse) error {
+	headers, err := json.Marshal(resp.Headers)
+	if err != nil {
+		return err
+	}
+	_, err = r.DB.GetConn().ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $2, headers = $3::jsonb, body = $4
+		WHERE key = $1 AND status_code IS NULL
+	`, key, resp.StatusCode, string(headers), resp.Body)
+	return err
+}
+
+// DeleteIdempotencyKeys removes keys claimed before the retention cutoff.
+func (r *Repository) DeleteIdempotencyKeys(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	res, err := r.DB.GetConn().ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, expiredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+	"github.com/lib/pq"
+)
+
+func (r *Repository) InsertItemsTx(ctx context.Context, tx *sql.Tx, orderID string, items []domain.Item) error {
+	for _, item := range items {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO order_items (order_id, sku, quantity, unit_price_minor, currency)
+             VALUES ($1, $2, $3, $4, $5)`,
+			orderID, item.ProductID, item.Quantity, item.UnitPrice, item.Currency,
+		)
+		if err != nil {
+			return fmt.Errorf("insert order item %s: %w", item.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// GetItems returns the items of the given orders keyed by order id.
+func (r *Repository) GetItems(ctx context.Context, orderIDs ...string) (map[string][]domain.Item, error) {
+	items := make(map[string][]domain.Item, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return items, nil
+	}
+
+	rows, err := r.DB.GetConn().QueryContext(ctx, `
+		SELECT order_id, sku, quantity, unit_price_minor, currency, refunded_quantity
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, sku
+	`, pq.Array(orderIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query order items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var item domain.Item
+		if err := rows.Scan(&orderID, &item.ProductID, &item.Quantity, &item.UnitPrice, &item.Currency, &item.RefundedQuantity); err != nil {
+			return nil, fmt.Errorf("scan order item: %w", err)
+		}
+		items[orderID] = append(items[orderID], item)
+	}
+	return items, rows.Err()
+}
+
+// GetItemsTx returns the items of the order inside tx.
+func (r *Repository) GetItemsTx(ctx context.Context, tx *sql.Tx, orderID string) ([]domain.Item, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT sku, quantity, unit_price_minor, currency, refunded_quantity
+		FROM order_items
+		WHERE order_id = $1
+		ORDER BY sku
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("query order items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]domain.Item, 0)
+	for rows.Next() {
+		var item domain.Item
+		if err := rows.Scan(&item.ProductID, &item.Quantity, &item.UnitPrice, &item.Currency, &item.RefundedQuantity); err != nil {
+			return nil, fmt.Errorf("scan order item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func toEventItems(items []domain.Item) []*events.Item {
+	evtItems := make([]*events.Item, len(items))
+	for i, it := range items {
+		evtItems[i] = &events.Item{
+			Id:             it.ProductID,
+			Quantity:       int32(it.Quantity),
+			UnitPriceMinor: it.UnitPrice,
+			Currency:       it.Currency,
+		}
+	}
+	return evtItems
+}
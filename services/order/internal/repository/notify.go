@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+)
+
+// OrderStatusChannel is the Postgres NOTIFY channel carrying order status changes.
+// Notifications are delivered on commit to every replica listening on it.
+const OrderStatusChannel = "order_status"
+
+type StatusNotification struct {
+	OrderID   string        `json:"order_id"`
+	Status    domain.Status `json:"status"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func (r *Repository) NotifyStatusTx(ctx context.Context, tx *sql.Tx, o *domain.Order) error {
+	b, err := json.Marshal(StatusNotification{OrderID: o.ID, Status: o.Status, UpdatedAt: o.UpdatedAt})
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, OrderStatusChannel, string(b))
+	return err
+}
@@ -1,40 +1,42 @@
 package repository
 
 import (
-	"context"
-	"database/sql"
-	"encoding/json"
-	"time"
-
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
 	"github.com/google/uuid"
 )
 
-type OutboxMessage struct {
-	ID            uuid.UUID       `json:"id"`
-	AggregateType string          `json:"aggregate_type"`
-	AggregateID   string          `json:"aggregate_id"`
-	EventType     string          `json:"event_type"`
-	Payload       json.RawMessage `json:"payload"`
-	Headers       map[string]any  `json:"headers"`
-	CreatedAt     time.Time       `json:"created_at"`
-}
+// inboxConsumer names the order service in the inbox of consumed events.
+const inboxConsumer = "order-service"
 
-func (r *Repository) InsertOutbox(ctx context.Context, tx *sql.Tx, msg OutboxMessage) error {
-	if msg.ID == uuid.Nil {
-		msg.ID = uuid.New()
+// newOrderOutboxMessage wraps env in an outbox message. The message id doubles
+// as the event id, so consumers see the same id however often it is delivered.
+func newOrderOutboxMessage(orderID string, env *events.OrderEventEnvelope) (db.OutboxMessage, error) {
+	msg := db.OutboxMessage{
+		ID:            uuid.New(),
+		AggregateType: "order",
+		AggregateID:   orderID,
+		EventType:     codec.EventType(env),
 	}
-	if msg.CreatedAt.IsZero() {
-		msg.CreatedAt = time.Now()
+	env.EventId = msg.ID.String()
+	payload, err := codec.Marshal(env)
+	if err != nil {
+		return msg, err
 	}
-	if msg.Headers == nil {
-		msg.Headers = map[string]any{}
+	msg.Payload = payload
+	return msg, nil
+}
+
+func toEventAddress(a domain.Address) *events.Address {
+	return &events.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
 	}
-	b, _ := json.Marshal(msg.Headers)
-	_, err := tx.ExecContext(
-		ctx,
-		`INSERT INTO outbox (id, aggregate_type, aggregate_id, event_type, payload, headers, created_at)
-         VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7)`,
-		msg.ID, msg.AggregateType, msg.AggregateID, msg.EventType, []byte(msg.Payload), string(b), msg.CreatedAt,
-	)
-	return err
 }
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+	"github.com/lib/pq"
+)
+
+// RequestRefund moves a paid order into Refunding on behalf of the customer
+// with id customerID, stores the refund of the given lines and asks payment,
+// through the outbox, to pay it back. It fails with domain.ErrIllegalTransition
+// when the order is not paid, with domain.ErrInvalidRefund when the lines do
+// not fit it and with domain.ErrOrderNotFound when it is another customer's.
+func (r *Repository) RequestRefund(ctx context.Context, orderID, customerID string, lines []domain.RefundLine, reason string) (*domain.Refund, domain.SagaStep, error) {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, domain.SagaStep{}, err
+	}
+
+	o, err := r.GetOrderTx(ctx, tx, orderID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, domain.SagaStep{}, err
+	}
+	if !o.OwnedBy(customerID) {
+		_ = tx.Rollback()
+		return nil, domain.SagaStep{}, domain.NewErrOrderNotFound(orderID)
+	}
+
+	var (
+		refund *domain.Refund
+		cause  domain.Cause
+		msgs   []db.OutboxMessage
+	)
+	if _, err := o.Status.Next(domain.EventRefundRequested); err == nil {
+		if o.Items, err = r.GetItemsTx(ctx, tx, orderID); err != nil {
+			_ = tx.Rollback()
+			return nil, domain.SagaStep{}, err
+		}
+		if refund, err = domain.NewRefund(o, lines, reason); err != nil {
+			_ = tx.Rollback()
+			return nil, domain.SagaStep{}, err
+		}
+		msg, err := newOrderOutboxMessage(orderID, &events.OrderEventEnvelope{
+			Event: &events.OrderEventEnvelope_RefundRequested{
+				RefundRequested: &events.RefundRequestedEvent{
+					Id:          orderID,
+					RefundId:    refund.ID,
+					Items:       toEventRefundItems(o.Items, refund.Lines),
+					AmountMinor: refund.Amount,
+					Currency:    refund.Currency,
+					Full:        refund.Full,
+					Reason:      refund.Reason,
+				},
+			},
+		})
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, domain.SagaStep{}, err
+		}
+		cause = domain.Cause{OutboxID: msg.ID.String()}
+		msgs = append(msgs, msg)
+	}
+
+	// an order that cannot be refunded gets the rejected step logged
+	step, err := r.applyTx(ctx, tx, o, domain.EventRefundRequested, cause, msgs...)
+	if err != nil && !errors.Is(err, domain.ErrIllegalTransition) {
+		_ = tx.Rollback()
+		return nil, step, err
+	}
+	applyErr := err
+
+	if refund != nil && applyErr == nil {
+		if err := r.InsertRefundTx(ctx, tx, refund); err != nil {
+			_ = tx.Rollback()
+			return nil, step, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, step, err
+	}
+	if applyErr != nil {
+		return nil, step, applyErr
+	}
+	return refund, step, nil
+}
+
+// CompleteRefundOnce records the reply of payment to a refund, consumed from
+// Kafka, and settles the order: a successful refund of everything left ends in
+// Refunded, anything else goes back to Paid, or on to Shipped or Delivered when
+// the shipment moved on in the meantime. Refunded items are handed back to
+// inventory through the outbox. A redelivered reply is skipped and reported
+// with processed set to false.
+func (r *Repository) CompleteRefundOnce(ctx context.Context, refundID string, succeeded bool, failureReason string, cause domain.Cause) (step domain.SagaStep, processed bool, err error) {
+	var applyErr error
+	processed, err = r.DB.HandleOnce(ctx, inboxConsumer, cause.EventID, func(tx *sql.Tx) error {
+		refund, err := r.GetRefundTx(ctx, tx, refundID)
+		if err != nil {
+			return err
+		}
+		if refund.Status != domain.RefundRequested {
+			// settled by an earlier reply with another event id
+			step = domain.SagaStep{OrderID: refund.OrderID}
+			return nil
+		}
+
+		evt := domain.EventRefundFailed
+		var msgs []db.OutboxMessage
+		if succeeded {
+			evt = domain.EventPartialRefundSucceeded
+			if refund.Full {
+				evt = domain.EventRefundSucceeded
+			}
+			msg, err := newOrderOutboxMessage(refund.OrderID, &events.OrderEventEnvelope{
+				Event: &events.OrderEventEnvelope_OrderRefunded{
+					OrderRefunded: &events.OrderRefundedEvent{
+						Id:       refund.OrderID,
+						RefundId: refund.ID,
+						Items:    toEventRefundItems(nil, refund.Lines),
+						Full:     refund.Full,
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			msgs = append(msgs, msg)
+		}
+
+		o, err := r.GetOrderTx(ctx, tx, refund.OrderID)
+		if err != nil {
+			return err
+		}
+		if o.Shipment, err = r.GetShipmentTx(ctx, tx, refund.OrderID); err != nil {
+			return err
+		}
+		step, applyErr = r.applyTx(ctx, tx, o, evt, cause, msgs...)
+		if errors.Is(applyErr, domain.ErrIllegalTransition) {
+			// the rejected step is logged and the event counts as processed
+			return nil
+		}
+		if applyErr != nil {
+			return applyErr
+		}
+
+		if succeeded {
+			refund.Status = domain.RefundSucceeded
+		} else {
+			refund.Status = domain.RefundFailed
+			refund.FailureReason = failureReason
+		}
+		refund.UpdatedAt = step.CreatedAt
+		return r.SettleRefundTx(ctx, tx, refund)
+	})
+	if err != nil {
+		return step, false, err
+	}
+	return step, processed, applyErr
+}
+
+func (r *Repository) InsertRefundTx(ctx context.Context, tx *sql.Tx, refund *domain.Refund) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO refunds (id, order_id, status, amount_minor, currency, full_refund, reason, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, refund.ID, refund.OrderID, refund.Status, refund.Amount, refund.Currency, refund.Full, refund.Reason, refund.CreatedAt, refund.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("insert refund %s: %w", refund.ID, err)
+	}
+	for _, line := range refund.Lines {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO refund_items (refund_id, sku, quantity, amount_minor)
+			VALUES ($1, $2, $3, $4)
+		`, refund.ID, line.ProductID, line.Quantity, line.Amount)
+		if err != nil {
+			return fmt.Errorf("insert refund item %s: %w", line.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// SettleRefundTx writes the outcome of the refund and, when it succeeded,
+// counts its lines as refunded on the order.
+func (r *Repository) SettleRefundTx(ctx context.Context, tx *sql.Tx, refund *domain.Refund) error {
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE refunds SET status = $1, failure_reason = $2, updated_at = $3 WHERE id = $4
+	`, refund.Status, refund.FailureReason, refund.UpdatedAt, refund.ID); err != nil {
+		return fmt.Errorf("update refund %s: %w", refund.ID, err)
+	}
+	if refund.Status != domain.RefundSucceeded {
+		return nil
+	}
+	for _, line := range refund.Lines {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE order_items SET refunded_quantity = refunded_quantity + $1
+			WHERE order_id = $2 AND sku = $3
+		`, line.Quantity, refund.OrderID, line.ProductID); err != nil {
+			return fmt.Errorf("update refunded quantity of %s: %w", line.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// GetRefundTx loads the refund with its lines and locks it until tx ends.
+func (r *Repository) GetRefundTx(ctx context.Context, tx *sql.Tx, id string) (*domain.Refund, error) {
+	refund, err := scanRefund(tx.QueryRowContext(ctx, `
+		SELECT id, order_id, status, amount_minor, currency, full_refund, reason, failure_reason, created_at, updated_at
+		FROM refunds
+		WHERE id = $1
+		FOR UPDATE
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewErrRefundNotFound(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query refund by id %s: %w", id, err)
+	}
+
+	lines, err := r.getRefundLines(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	refund.Lines = lines[id]
+	return refund, nil
+}
+
+// openRefundRequestTx rebuilds the RefundRequested of the refund of the order
+// that payment has not answered yet.
+func (r *Repository) openRefundRequestTx(ctx context.Context, tx *sql.Tx, orderID string) (*events.OrderEventEnvelope, error) {
+	var id string
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM refunds
+		WHERE order_id = $1 AND status = $2
+		ORDER BY created_at DESC, id
+		LIMIT 1
+	`, orderID, domain.RefundRequested).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no open refund of order %s: %w", orderID, domain.ErrRefundNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query open refund of order %s: %w", orderID, err)
+	}
+
+	refund, err := r.GetRefundTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := r.GetItemsTx(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &events.OrderEventEnvelope{
+		Event: &events.OrderEventEnvelope_RefundRequested{
+			RefundRequested: &events.RefundRequestedEvent{
+				Id:          orderID,
+				RefundId:    refund.ID,
+				Items:       toEventRefundItems(items, refund.Lines),
+				AmountMinor: refund.Amount,
+				Currency:    refund.Currency,
+				Full:        refund.Full,
+				Reason:      refund.Reason,
+			},
+		},
+	}, nil
+}
+
+// ListRefunds returns the refunds of the order, oldest first.
+func (r *Repository) ListRefunds(ctx context.Context, orderID string) ([]domain.Refund, error) {
+	rows, err := r.DB.GetConn().QueryContext(ctx, `
+		SELECT id, order_id, status, amount_minor, currency, full_refund, reason, failure_reason, created_at, updated_at
+		FROM refunds
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("list refunds: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := make([]domain.Refund, 0)
+	ids := make([]string, 0)
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan refund: %w", err)
+		}
+		refunds = append(refunds, *refund)
+		ids = append(ids, refund.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lines, err := r.getRefundLines(ctx, r.DB.GetConn(), ids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range refunds {
+		refunds[i].Lines = lines[refunds[i].ID]
+	}
+	return refunds, nil
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// getRefundLines returns the lines of the given refunds keyed by refund id.
+func (r *Repository) getRefundLines(ctx context.Context, q querier, refundIDs ...string) (map[string][]domain.RefundLine, error) {
+	lines := make(map[string][]domain.RefundLine, len(refundIDs))
+	if len(refundIDs) == 0 {
+		return lines, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT refund_id, sku, quantity, amount_minor
+		FROM refund_items
+		WHERE refund_id = ANY($1)
+		ORDER BY refund_id, sku
+	`, pq.Array(refundIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query refund items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var refundID string
+		var line domain.RefundLine
+		if err := rows.Scan(&refundID, &line.ProductID, &line.Quantity, &line.Amount); err != nil {
+			return nil, fmt.Errorf("scan refund item: %w", err)
+		}
+		lines[refundID] = append(lines[refundID], line)
+	}
+	return lines, rows.Err()
+}
+
+func scanRefund(row interface{ Scan(dest ...any) error }) (*domain.Refund, error) {
+	var refund domain.Refund
+	if err := row.Scan(&refund.ID, &refund.OrderID, &refund.Status, &refund.Amount, &refund.Currency, &refund.Full,
+		&refund.Reason, &refund.FailureReason, &refund.CreatedAt, &refund.UpdatedAt); err != nil {
+		return nil, err
+	}
+	refund.Lines = make([]domain.RefundLine, 0)
+	return &refund, nil
+}
+
+// toEventRefundItems describes refund lines as event items, with the unit
+// price of the order line when items are given.
+func toEventRefundItems(items []domain.Item, lines []domain.RefundLine) []*events.Item {
+	prices := make(map[string]domain.Item, len(items))
+	for _, item := range items {
+		prices[item.ProductID] = item
+	}
+	evtItems := make([]*events.Item, len(lines))
+	for i, line := range lines {
+		evtItems[i] = &events.Item{
+			Id:             line.ProductID,
+			Quantity:       int32(line.Quantity),
+			UnitPriceMinor: prices[line.ProductID].UnitPrice,
+			Currency:       prices[line.ProductID].Currency,
+		}
+	}
+	return evtItems
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -10,7 +11,6 @@ import (
 	"github.com/axmz/go-saga-microservices/lib/adapter/db"
 	"github.com/axmz/go-saga-microservices/pkg/proto/events"
 	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
-	"google.golang.org/protobuf/proto"
 )
 
 type Repository struct {
@@ -21,48 +21,55 @@ func New(db *db.DB) *Repository {
 	return &Repository{DB: db}
 }
 
-func (r *Repository) CreateOrder(ctx context.Context, o *domain.Order) error {
+// CreateOrder stores the order and its OrderCreated outbox message. With idem
+// set, the Idempotency-Key is claimed in the same transaction.
+func (r *Repository) CreateOrder(ctx context.Context, o *domain.Order, idem *domain.Idempotency) error {
 	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
 		return err
 	}
 
-	var itemIDs string
-	for i, item := range o.Items {
-		if i > 0 {
-			itemIDs += ","
-		}
-		itemIDs += item.ProductID
-	}
-	q := `INSERT INTO orders (id, item_ids, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
-	if _, err := tx.ExecContext(ctx, q, o.ID, itemIDs, o.Status, o.CreatedAt, o.UpdatedAt); err != nil {
+	if err := r.CreateOrderTx(ctx, tx, o); err != nil {
 		_ = tx.Rollback()
 		return err
 	}
 
-	evtItems := make([]*events.Item, len(o.Items))
-	for i, it := range o.Items {
-		evtItems[i] = &events.Item{Id: it.ProductID}
+	if idem != nil {
+		if err := r.ClaimIdempotencyKeyTx(ctx, tx, idem, o.ID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
 	}
-	env := &events.OrderEventEnvelope{
+
+	msg, err := newOrderOutboxMessage(o.ID, &events.OrderEventEnvelope{
 		Event: &events.OrderEventEnvelope_OrderCreated{
 			OrderCreated: &events.OrderCreatedEvent{
-				Id:    o.ID,
-				Items: evtItems,
+				Id:              o.ID,
+				Items:           toEventItems(o.Items),
+				SubtotalMinor:   o.Subtotal,
+				TotalMinor:      o.Total,
+				Currency:        o.Currency,
+				CustomerId:      o.Customer.ID,
+				Email:           o.Customer.Email,
+				ShippingAddress: toEventAddress(o.Customer.ShippingAddress),
 			},
 		},
-	}
-	payload, err := proto.Marshal(env)
+	})
 	if err != nil {
 		_ = tx.Rollback()
 		return err
 	}
+	if err := r.DB.InsertOutbox(ctx, tx, msg); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 
-	if err := r.InsertOutbox(ctx, tx, OutboxMessage{
-		AggregateType: "order",
-		AggregateID:   o.ID,
-		EventType:     "OrderCreated",
-		Payload:       payload,
+	if err := r.InsertStatusChangeTx(ctx, tx, domain.StatusChange{
+		OrderID:   o.ID,
+		ToStatus:  o.Status,
+		Event:     domain.EventCreated,
+		Cause:     domain.Cause{OutboxID: msg.ID.String()},
+		CreatedAt: o.CreatedAt,
 	}); err != nil {
 		_ = tx.Rollback()
 		return err
@@ -72,42 +79,178 @@ func (r *Repository) CreateOrder(ctx context.Context, o *domain.Order) error {
 }
 
 func (r *Repository) CreateOrderTx(ctx context.Context, tx *sql.Tx, o *domain.Order) error {
-	var itemIDs string
-	for i, item := range o.Items {
-		if i > 0 {
-			itemIDs += ","
-		}
-		itemIDs += item.ProductID
+	address, err := json.Marshal(o.Customer.ShippingAddress)
+	if err != nil {
+		return err
 	}
-	q := `INSERT INTO orders (id, item_ids, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
-	_, err := tx.ExecContext(ctx, q, o.ID, itemIDs, o.Status, o.CreatedAt, o.UpdatedAt)
-	return err
+	q := `INSERT INTO orders (id, customer_id, email, shipping_address, status, subtotal_minor, total_minor, currency, created_at, updated_at, version)
+		VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7, $8, $9, $10, $11)`
+	if _, err := tx.ExecContext(ctx, q, o.ID, o.Customer.ID, o.Customer.Email, string(address), o.Status, o.Subtotal, o.Total, o.Currency, o.CreatedAt, o.UpdatedAt, o.Version); err != nil {
+		return err
+	}
+	return r.InsertItemsTx(ctx, tx, o.ID, o.Items)
 }
 
 func (r *Repository) GetOrder(ctx context.Context, id string) (*domain.Order, error) {
 	row := r.DB.GetConn().QueryRowContext(ctx, `
-		SELECT id, status, item_ids, created_at, updated_at
+		SELECT id, customer_id, email, shipping_address, status, subtotal_minor, total_minor, currency, created_at, updated_at, version
 		FROM orders
 		WHERE id = $1
 	`, id)
+	o, err := scanOrder(row, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	o.Items = items[id]
+
+	if o.Shipment, err = r.GetShipment(ctx, id); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// GetOrderTx loads the order without its items and without locking its row;
+// writes based on it go through UpdateOrderTx or CheckVersionTx.
+func (r *Repository) GetOrderTx(ctx context.Context, tx *sql.Tx, id string) (*domain.Order, error) {
+	row := tx.QueryRowContext(ctx, `
+		SELECT id, customer_id, email, shipping_address, status, subtotal_minor, total_minor, currency, created_at, updated_at, version
+		FROM orders
+		WHERE id = $1
+	`, id)
+	return scanOrder(row, id)
+}
+
+// ListOrders returns up to f.Limit orders matching f, newest first.
+func (r *Repository) ListOrders(ctx context.Context, f domain.OrderFilter) ([]domain.Order, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Status != "" {
+		where = append(where, "status = "+arg(f.Status))
+	}
+	if !f.CreatedFrom.IsZero() {
+		where = append(where, "created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		where = append(where, "created_at < "+arg(f.CreatedTo))
+	}
+	if f.CustomerID != "" {
+		where = append(where, "customer_id = "+arg(f.CustomerID))
+	}
+	if f.SKU != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM order_items oi WHERE oi.order_id = orders.id AND oi.sku = "+arg(f.SKU)+")")
+	}
+	if f.After != nil {
+		where = append(where, fmt.Sprintf("(created_at, id) < (%s, %s)", arg(f.After.CreatedAt), arg(f.After.ID)))
+	}
+
+	q := `SELECT id, customer_id, email, shipping_address, status, subtotal_minor, total_minor, currency, created_at, updated_at, version FROM orders`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	q += ` ORDER BY created_at DESC, id DESC LIMIT ` + arg(f.Limit)
+
+	rows, err := r.DB.GetConn().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]domain.Order, 0, f.Limit)
+	ids := make([]string, 0, f.Limit)
+	for rows.Next() {
+		o, err := scanOrderFields(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan order: %w", err)
+		}
+		orders = append(orders, *o)
+		ids = append(ids, o.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.GetItems(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Items = items[orders[i].ID]
+	}
+	return orders, nil
+}
 
-	var o domain.Order
-	var itemIDs string
-	err := row.Scan(&o.ID, &o.Status, &itemIDs, &o.CreatedAt, &o.UpdatedAt)
+func scanOrder(row *sql.Row, id string) (*domain.Order, error) {
+	o, err := scanOrderFields(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.NewErrOrderNotFound(id)
 		}
 		return nil, fmt.Errorf("query order by id %s: %w", id, err)
 	}
-	o.Items = make([]domain.Item, 0)
-	for _, itemID := range strings.Split(itemIDs, ",") {
-		o.Items = append(o.Items, domain.Item{ProductID: itemID})
+	return o, nil
+}
+
+func scanOrderFields(row interface{ Scan(dest ...any) error }) (*domain.Order, error) {
+	var (
+		o       domain.Order
+		address []byte
+	)
+	if err := row.Scan(&o.ID, &o.Customer.ID, &o.Customer.Email, &address, &o.Status, &o.Subtotal, &o.Total, &o.Currency, &o.CreatedAt, &o.UpdatedAt, &o.Version); err != nil {
+		return nil, err
+	}
+	if len(address) > 0 {
+		if err := json.Unmarshal(address, &o.Customer.ShippingAddress); err != nil {
+			return nil, fmt.Errorf("decode shipping address of order %s: %w", o.ID, err)
+		}
 	}
+	o.Items = make([]domain.Item, 0)
 	return &o, nil
 }
 
-func (r *Repository) UpdateOrder(ctx context.Context, o *domain.Order) error {
-	_, err := r.DB.GetConn().ExecContext(ctx, `UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3`, o.Status, o.UpdatedAt, o.ID)
+// UpdateOrderTx writes the order if it is still at o.Version and bumps the
+// version, otherwise it fails with domain.ErrConcurrentModification.
+func (r *Repository) UpdateOrderTx(ctx context.Context, tx *sql.Tx, o *domain.Order) error {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE orders
+		SET status = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND version = $4
+	`, o.Status, o.UpdatedAt, o.ID, o.Version)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.NewErrConcurrentModification(o.ID, o.Version)
+	}
+	o.Version++
+	return nil
+}
+
+// CheckVersionTx fails with domain.ErrConcurrentModification when the order is
+// no longer at o.Version, and otherwise keeps it there until tx ends. It is for
+// decisions taken on o that do not update it.
+func (r *Repository) CheckVersionTx(ctx context.Context, tx *sql.Tx, o *domain.Order) error {
+	var one int
+	err := tx.QueryRowContext(ctx, `
+		SELECT 1 FROM orders WHERE id = $1 AND version = $2 FOR UPDATE
+	`, o.ID, o.Version).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewErrConcurrentModification(o.ID, o.Version)
+	}
 	return err
 }
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+)
+
+func (r *Repository) InsertSagaStep(ctx context.Context, tx *sql.Tx, step domain.SagaStep) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO saga_steps (order_id, event, from_status, to_status, accepted, reason, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		step.OrderID, step.Event, step.FromStatus, step.ToStatus, step.Accepted, step.Reason, step.CreatedAt,
+	)
+	return err
+}
+
+// TransitionOrder feeds evt into the saga of the given order. The order is read
+// without a lock; when it changes in the meantime nothing is written and
+// domain.ErrConcurrentModification is returned. The step is logged whether or
+// not the transition is legal; the order itself is only updated, its status
+// history extended with cause, and msgs only written to the outbox, for legal
+// transitions, otherwise an ErrIllegalTransition is returned.
+func (r *Repository) TransitionOrder(ctx context.Context, orderID string, evt domain.Event, cause domain.Cause, msgs ...db.OutboxMessage) (domain.SagaStep, error) {
+	return r.transition(ctx, func(tx *sql.Tx) (domain.SagaStep, error) {
+		return r.TransitionOrderTx(ctx, tx, orderID, evt, cause, msgs...)
+	})
+}
+
+// TransitionCustomerOrder is TransitionOrder on behalf of the customer with id
+// customerID. The order of another customer is reported as not found, so that
+// order ids cannot be probed.
+func (r *Repository) TransitionCustomerOrder(ctx context.Context, orderID, customerID string, evt domain.Event, cause domain.Cause, msgs ...db.OutboxMessage) (domain.SagaStep, error) {
+	return r.transition(ctx, func(tx *sql.Tx) (domain.SagaStep, error) {
+		o, err := r.GetOrderTx(ctx, tx, orderID)
+		if err != nil {
+			return domain.SagaStep{}, err
+		}
+		if !o.OwnedBy(customerID) {
+			return domain.SagaStep{}, domain.NewErrOrderNotFound(orderID)
+		}
+		return r.applyTx(ctx, tx, o, evt, cause, msgs...)
+	})
+}
+
+// transition runs apply in a transaction that is committed unless apply
+// fails with another error than ErrIllegalTransition.
+func (r *Repository) transition(ctx context.Context, apply func(tx *sql.Tx) (domain.SagaStep, error)) (domain.SagaStep, error) {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return domain.SagaStep{}, err
+	}
+
+	step, err := apply(tx)
+	if err != nil && !errors.Is(err, domain.ErrIllegalTransition) {
+		_ = tx.Rollback()
+		return step, err
+	}
+	applyErr := err
+
+	if err := tx.Commit(); err != nil {
+		return step, err
+	}
+	return step, applyErr
+}
+
+// TransitionOrderOnce is TransitionOrder for an event consumed from Kafka. The
+// event is recorded in the inbox in the same transaction, a redelivered event
+// is skipped and reported with processed set to false.
+func (r *Repository) TransitionOrderOnce(ctx context.Context, orderID string, evt domain.Event, cause domain.Cause) (step domain.SagaStep, processed bool, err error) {
+	var applyErr error
+	processed, err = r.DB.HandleOnce(ctx, inboxConsumer, cause.EventID, func(tx *sql.Tx) error {
+		step, applyErr = r.TransitionOrderTx(ctx, tx, orderID, evt, cause)
+		if errors.Is(applyErr, domain.ErrIllegalTransition) {
+			// the rejected step is logged and the event counts as processed
+			return nil
+		}
+		return applyErr
+	})
+	if err != nil {
+		return step, false, err
+	}
+	return step, processed, applyErr
+}
+
+// TransitionOrderTx does the work of TransitionOrder inside tx. On an
+// ErrIllegalTransition tx still holds the saga step and should be committed.
+func (r *Repository) TransitionOrderTx(ctx context.Context, tx *sql.Tx, orderID string, evt domain.Event, cause domain.Cause, msgs ...db.OutboxMessage) (domain.SagaStep, error) {
+	o, err := r.GetOrderTx(ctx, tx, orderID)
+	if err != nil {
+		return domain.SagaStep{}, err
+	}
+	return r.applyTx(ctx, tx, o, evt, cause, msgs...)
+}
+
+// applyTx is TransitionOrderTx for an order already loaded in tx.
+func (r *Repository) applyTx(ctx context.Context, tx *sql.Tx, o *domain.Order, evt domain.Event, cause domain.Cause, msgs ...db.OutboxMessage) (domain.SagaStep, error) {
+	step, applyErr := o.Apply(evt)
+	if step.Accepted {
+		if err := r.UpdateOrderTx(ctx, tx, o); err != nil {
+			return step, err
+		}
+		if step.ToStatus != step.FromStatus {
+			if err := r.InsertStatusChangeTx(ctx, tx, domain.StatusChange{
+				OrderID:    o.ID,
+				FromStatus: step.FromStatus,
+				ToStatus:   step.ToStatus,
+				Event:      evt,
+				Cause:      cause,
+				CreatedAt:  step.CreatedAt,
+			}); err != nil {
+				return step, err
+			}
+		}
+		if err := r.NotifyStatusTx(ctx, tx, o); err != nil {
+			return step, err
+		}
+		for _, msg := range msgs {
+			if err := r.DB.InsertOutbox(ctx, tx, msg); err != nil {
+				return step, err
+			}
+		}
+	} else if err := r.CheckVersionTx(ctx, tx, o); err != nil {
+		// a rejection is only as good as the status it was based on
+		return step, err
+	}
+
+	if err := r.InsertSagaStep(ctx, tx, step); err != nil {
+		return step, err
+	}
+	return step, applyErr
+}
+
+// ExpireOrder moves a stuck order into compensation and asks inventory, through
+// the outbox, to release whatever it may have reserved for it.
+func (r *Repository) ExpireOrder(ctx context.Context, orderID string) (domain.SagaStep, error) {
+	msg, err := newOrderOutboxMessage(orderID, &events.OrderEventEnvelope{
+		Event: &events.OrderEventEnvelope_OrderExpired{
+			OrderExpired: &events.OrderExpiredEvent{
+				Id: orderID,
+			},
+		},
+	})
+	if err != nil {
+		return domain.SagaStep{}, err
+	}
+
+	return r.TransitionOrder(ctx, orderID, domain.EventExpired, domain.Cause{OutboxID: msg.ID.String()}, msg)
+}
+
+// RedriveOrder times out an order that waits in Compensating, Cancelling or
+// Refunding and publishes the command it waits on again through the outbox:
+// OrderExpired or OrderCancelled for inventory to release the items, or the
+// open refund's RefundRequested for payment. Both answer a repeated command
+// like the first one. An order in any other status gets the rejected step
+// logged and domain.ErrIllegalTransition.
+func (r *Repository) RedriveOrder(ctx context.Context, orderID string) (domain.SagaStep, error) {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return domain.SagaStep{}, err
+	}
+
+	o, err := r.GetOrderTx(ctx, tx, orderID)
+	if err != nil {
+		_ = tx.Rollback()
+		return domain.SagaStep{}, err
+	}
+
+	var env *events.OrderEventEnvelope
+	switch o.Status {
+	case domain.StatusCompensating:
+		env = &events.OrderEventEnvelope{
+			Event: &events.OrderEventEnvelope_OrderExpired{
+				OrderExpired: &events.OrderExpiredEvent{Id: orderID},
+			},
+		}
+	case domain.StatusCancelling:
+		env = &events.OrderEventEnvelope{
+			Event: &events.OrderEventEnvelope_OrderCancelled{
+				OrderCancelled: &events.OrderCancelledEvent{Id: orderID},
+			},
+		}
+	case domain.StatusRefunding:
+		if env, err = r.openRefundRequestTx(ctx, tx, orderID); err != nil {
+			_ = tx.Rollback()
+			return domain.SagaStep{}, err
+		}
+	}
+
+	var cause domain.Cause
+	var msgs []db.OutboxMessage
+	if env != nil {
+		msg, err := newOrderOutboxMessage(orderID, env)
+		if err != nil {
+			_ = tx.Rollback()
+			return domain.SagaStep{}, err
+		}
+		cause = domain.Cause{OutboxID: msg.ID.String()}
+		msgs = append(msgs, msg)
+	}
+
+	step, err := r.applyTx(ctx, tx, o, domain.EventTimedOut, cause, msgs...)
+	if err != nil && !errors.Is(err, domain.ErrIllegalTransition) {
+		_ = tx.Rollback()
+		return step, err
+	}
+	applyErr := err
+
+	if err := tx.Commit(); err != nil {
+		return step, err
+	}
+	return step, applyErr
+}
+
+// CancelOrder moves the order into Cancelling on behalf of the customer with
+// id customerID and tells inventory and payment, through the outbox, to undo
+// their part.
+func (r *Repository) CancelOrder(ctx context.Context, orderID, customerID string) (domain.SagaStep, error) {
+	msg, err := newOrderOutboxMessage(orderID, &events.OrderEventEnvelope{
+		Event: &events.OrderEventEnvelope_OrderCancelled{
+			OrderCancelled: &events.OrderCancelledEvent{
+				Id: orderID,
+			},
+		},
+	})
+	if err != nil {
+		return domain.SagaStep{}, err
+	}
+
+	return r.TransitionCustomerOrder(ctx, orderID, customerID, domain.EventCancelled, domain.Cause{OutboxID: msg.ID.String()}, msg)
+}
+
+// ListStaleOrders returns ids of orders that have been in status since before the given time.
+func (r *Repository) ListStaleOrders(ctx context.Context, status domain.Status, before time.Time, limit int) ([]string, error) {
+	rows, err := r.DB.GetConn().QueryContext(ctx, `
+		SELECT id
+		FROM orders
+		WHERE status = $1 AND updated_at < $2
+		ORDER BY updated_at
+		LIMIT $3
+	`, status, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+)
+
+// RecordShipmentOnce stores what the shipping service reported about the
+// shipment of an order and moves the order along with it, in one transaction
+// with the inbox entry of the event. The shipment is kept even when the order
+// rejects the event, so that a parcel on its way can always be tracked.
+func (r *Repository) RecordShipmentOnce(ctx context.Context, s domain.Shipment, evt domain.Event, cause domain.Cause) (step domain.SagaStep, processed bool, err error) {
+	var applyErr error
+	processed, err = r.DB.HandleOnce(ctx, inboxConsumer, cause.EventID, func(tx *sql.Tx) error {
+		step, applyErr = r.TransitionOrderTx(ctx, tx, s.OrderID, evt, cause)
+		if applyErr != nil && !errors.Is(applyErr, domain.ErrIllegalTransition) {
+			return applyErr
+		}
+		return r.UpsertShipmentTx(ctx, tx, s)
+	})
+	if err != nil {
+		return step, false, err
+	}
+	return step, processed, applyErr
+}
+
+// UpsertShipmentTx stores the shipment of an order. A shipment never moves
+// back to an earlier status, and a report without a tracking URL keeps the
+// one known.
+func (r *Repository) UpsertShipmentTx(ctx context.Context, tx *sql.Tx, s domain.Shipment) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO shipments (order_id, shipment_id, carrier, tracking_number, tracking_url, status, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (order_id) DO UPDATE
+		SET shipment_id = EXCLUDED.shipment_id,
+			carrier = EXCLUDED.carrier,
+			tracking_number = EXCLUDED.tracking_number,
+			tracking_url = COALESCE(NULLIF(EXCLUDED.tracking_url, ''), shipments.tracking_url),
+			status = EXCLUDED.status,
+			updated_at = EXCLUDED.updated_at
+		WHERE array_position(ARRAY['Created', 'Dispatched', 'Delivered'], EXCLUDED.status)
+			>= array_position(ARRAY['Created', 'Dispatched', 'Delivered'], shipments.status)
+	`, s.OrderID, s.ID, s.Carrier, s.TrackingNumber, s.TrackingURL, s.Status, s.UpdatedAt)
+	return err
+}
+
+const selectShipment = `
+	SELECT shipment_id, carrier, tracking_number, tracking_url, status, updated_at
+	FROM shipments
+	WHERE order_id = $1
+`
+
+// GetShipment returns the shipment of the order, or nil when it was not
+// shipped yet.
+func (r *Repository) GetShipment(ctx context.Context, orderID string) (*domain.Shipment, error) {
+	return scanShipment(r.DB.GetConn().QueryRowContext(ctx, selectShipment, orderID), orderID)
+}
+
+// GetShipmentTx is GetShipment inside tx.
+func (r *Repository) GetShipmentTx(ctx context.Context, tx *sql.Tx, orderID string) (*domain.Shipment, error) {
+	return scanShipment(tx.QueryRowContext(ctx, selectShipment, orderID), orderID)
+}
+
+func scanShipment(row *sql.Row, orderID string) (*domain.Shipment, error) {
+	s := domain.Shipment{OrderID: orderID}
+	err := row.Scan(&s.ID, &s.Carrier, &s.TrackingNumber, &s.TrackingURL, &s.Status, &s.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
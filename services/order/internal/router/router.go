@@ -1,6 +1,7 @@
 package router
 
 import (
+	"expvar"
 	"net/http"
 
 	"github.com/axmz/go-saga-microservices/services/order/internal/handler"
@@ -10,7 +11,21 @@ import (
 func New(svc *service.Service, h *handler.Handler) *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /orders", h.CreateOrder)
+	mux.HandleFunc("GET /orders", h.ListOrders)
 	mux.HandleFunc("GET /orders/{orderID}", h.GetOrder)
+	mux.HandleFunc("GET /orders/{orderID}/history", h.GetOrderHistory)
+	mux.HandleFunc("POST /orders/{orderID}/cancel", h.CancelOrder)
+	mux.HandleFunc("POST /orders/{orderID}/refund", h.RefundOrder)
+	mux.HandleFunc("GET /orders/{orderID}/refunds", h.ListRefunds)
+	mux.HandleFunc("GET /orders/{orderID}/events", h.OrderStatusEvents)
 	mux.HandleFunc("GET /orders/ws", h.OrderStatusWS)
 	return mux
 }
+
+// Debug serves the expvar metrics. It is mounted on the internal debug
+// listener, not on the public API.
+func Debug() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	return mux
+}
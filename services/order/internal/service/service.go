@@ -2,105 +2,497 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/services/order/internal/client"
 	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
-	"github.com/axmz/go-saga-microservices/services/order/internal/publisher"
 	"github.com/axmz/go-saga-microservices/services/order/internal/repository"
-	"github.com/axmz/go-saga-microservices/services/order/internal/sync"
+	"github.com/axmz/go-saga-microservices/services/order/internal/watcher"
 )
 
 type Service struct {
-	Repo  *repository.Repository
-	Kafka *publisher.Publisher
-	Sync  *sync.Sync
+	cfg       *config.Config
+	Repo      *repository.Repository
+	Watcher   *watcher.Watcher
+	Inventory client.InventoryClient
 }
 
-func New(repo *repository.Repository, kafka *publisher.Publisher, s *sync.Sync) *Service {
+func New(cfg *config.Config, repo *repository.Repository, w *watcher.Watcher, inv client.InventoryClient) *Service {
 	return &Service{
-		Repo:  repo,
-		Kafka: kafka,
-		Sync:  s,
+		cfg:       cfg,
+		Repo:      repo,
+		Watcher:   w,
+		Inventory: inv,
 	}
 }
 
-func (s *Service) CreateOrder(ctx context.Context, items []domain.Item) (*domain.Order, error) {
-	order := domain.NewOrder(items)
-	ch := s.Sync.Push(order.ID)
-	defer func() {
-		s.Sync.Remove(order.ID)
-	}()
+// CreateOrder stores the order and waits for the inventory reply. When no reply
+// arrives within the configured timeout the order is returned as it is together
+// with domain.ErrOrderPending; the saga keeps going in the background.
+// A nil order means nothing was stored.
+func (s *Service) CreateOrder(ctx context.Context, customer domain.Customer, items []domain.Item, idem *domain.Idempotency) (*domain.Order, error) {
+	if err := s.priceItems(ctx, items); err != nil {
+		return nil, err
+	}
+	order := domain.NewOrder(customer, items)
+	updates, stop := s.Watcher.Watch(order.ID)
+	defer stop()
 
-	if err := s.Repo.CreateOrder(ctx, order); err != nil {
+	if err := s.Repo.CreateOrder(ctx, order, s.withRetention(idem)); err != nil {
 		return nil, err
 	}
-	slog.Info("[OrderService] Created order: %s, status: %s", order.ID, order.Status)
-	// TODO: handle ctx cancellation
-	status := <-ch
-	if status != sync.OK {
+	slog.Info("[OrderService] Created order:", "orderID", order.ID, "customerID", customer.ID, "status", order.Status)
+
+	status, err := s.awaitReservation(ctx, order.ID, updates)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderPending) {
+			slog.Warn("[OrderService] No inventory reply in time:", "orderID", order.ID)
+		}
+		return order, err
+	}
+
+	order.Status = status
+	if status != domain.StatusAwaitingPayment {
 		return order, fmt.Errorf("order created but failed to reserve items: %s", order.ID)
 	}
-	// By this point the order is in AwaitingPayment status, update by the consumer handlers.
-	// We cut corners here.
-	order.Status = domain.StatusAwaitingPayment
 	slog.Info("[OrderService] Order items reserved successfully:", "orderID", order.ID)
 	return order, nil
 }
 
+// PlaceOrder stores the order and returns right away without waiting for the
+// saga; callers follow its progress through GetOrder.
+func (s *Service) PlaceOrder(ctx context.Context, customer domain.Customer, items []domain.Item, idem *domain.Idempotency) (*domain.Order, error) {
+	if err := s.priceItems(ctx, items); err != nil {
+		return nil, err
+	}
+	order := domain.NewOrder(customer, items)
+	if err := s.Repo.CreateOrder(ctx, order, s.withRetention(idem)); err != nil {
+		return nil, err
+	}
+	slog.Info("[OrderService] Placed order:", "orderID", order.ID, "customerID", customer.ID, "status", order.Status)
+	return order, nil
+}
+
+// priceItems sets the unit price and currency of each item to the current
+// inventory price, so that what the order charges never comes from the client.
+// An item without a price fails with domain.ErrUnknownProduct. Inventory
+// prices all products in one currency, so the items of an order share it.
+func (s *Service) priceItems(ctx context.Context, items []domain.Item) error {
+	skus := make([]string, len(items))
+	for i, item := range items {
+		skus[i] = item.ProductID
+	}
+	resp, err := s.Inventory.GetPrices(ctx, skus)
+	if err != nil {
+		return fmt.Errorf("get prices: %w", err)
+	}
+	prices := make(map[string]int64, len(resp.Prices))
+	currencies := make(map[string]string, len(resp.Prices))
+	for _, p := range resp.Prices {
+		prices[p.Sku] = p.PriceMinor
+		currencies[p.Sku] = p.Currency
+	}
+
+	for i := range items {
+		price, ok := prices[items[i].ProductID]
+		if !ok {
+			return domain.NewErrUnknownProduct(items[i].ProductID)
+		}
+		currency := currencies[items[i].ProductID]
+		if currency == "" {
+			currency = domain.DefaultCurrency
+		}
+		items[i].UnitPrice = price
+		items[i].Currency = currency
+	}
+	return nil
+}
+
+// LookupIdempotencyRecord returns the record of an earlier request with the
+// same Idempotency-Key, or nil when the key is new. A key reused with a
+// different request fails with domain.ErrIdempotencyKeyReused.
+func (s *Service) LookupIdempotencyRecord(ctx context.Context, idem *domain.Idempotency) (*domain.IdempotencyRecord, error) {
+	idem = s.withRetention(idem)
+	rec, err := s.Repo.GetIdempotencyRecord(ctx, idem.Key, idem.ExpiredBefore)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	if rec.RequestHash != idem.RequestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	return rec, nil
+}
+
+func (s *Service) SaveIdempotentResponse(ctx context.Context, idem *domain.Idempotency, resp domain.IdempotentResponse) error {
+	return s.Repo.SaveIdempotentResponse(ctx, idem.Key, resp)
+}
+
+// PurgeIdempotencyKeys deletes keys past retention and returns how many went.
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	return s.Repo.DeleteIdempotencyKeys(ctx, time.Now().Add(-s.cfg.Order.Idempotency.Retention))
+}
+
+func (s *Service) withRetention(idem *domain.Idempotency) *domain.Idempotency {
+	if idem == nil {
+		return nil
+	}
+	withCutoff := *idem
+	withCutoff.ExpiredBefore = time.Now().Add(-s.cfg.Order.Idempotency.Retention)
+	return &withCutoff
+}
+
+// awaitReservation blocks until the order leaves Pending, the reply timeout
+// elapses or ctx is cancelled.
+func (s *Service) awaitReservation(ctx context.Context, orderID string, updates <-chan watcher.Update) (domain.Status, error) {
+	if timeout := s.cfg.Order.Saga.ReplyTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return domain.StatusPending, domain.ErrOrderPending
+			}
+			return domain.StatusPending, ctx.Err()
+		case u := <-updates:
+			status := u.Status
+			if status == "" {
+				// notifications may have been missed, look at the row itself
+				o, err := s.Repo.GetOrder(ctx, orderID)
+				if err != nil {
+					return domain.StatusPending, err
+				}
+				status = o.Status
+			}
+			if status != domain.StatusPending {
+				return status, nil
+			}
+		}
+	}
+}
+
 func (s *Service) GetOrder(ctx context.Context, orderId string) (*domain.Order, error) {
 	return s.Repo.GetOrder(ctx, orderId)
 }
 
-func (s *Service) UpdateOrder(ctx context.Context, orderID string, status domain.Status) error {
-	// TODO: do better
-	o := domain.NewOrder(nil)
-	o.ID = orderID
-	o.Status = status
+// GetOrderHistory returns the status changes of an existing order, oldest first.
+func (s *Service) GetOrderHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	if _, err := s.Repo.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetStatusHistory(ctx, orderID)
+}
 
-	err := s.Repo.UpdateOrder(ctx, o)
-	slog.Info("Updating order status:", "orderID", orderID, "status", status)
+// FollowStatus reports the current status of the order and then every change
+// of it, fed by the status notifications rather than by polling. The channel
+// is closed after a settled status, or when ctx ends. FollowStatus fails
+// right away when the order does not exist.
+func (s *Service) FollowStatus(ctx context.Context, orderID string) (<-chan watcher.Update, error) {
+	// watch first so that no change between the read and the watch is lost
+	updates, stop := s.Watcher.Watch(orderID)
+	o, err := s.Repo.GetOrder(ctx, orderID)
 	if err != nil {
-		return err
+		stop()
+		return nil, err
 	}
-	return nil
+
+	out := make(chan watcher.Update)
+	go func() {
+		defer stop()
+		defer close(out)
+
+		last := watcher.Update{OrderID: o.ID, Status: o.Status, UpdatedAt: o.UpdatedAt}
+		for {
+			select {
+			case out <- last:
+			case <-ctx.Done():
+				return
+			}
+			if last.Status.IsSettled() {
+				return
+			}
+
+			next, err := s.nextStatus(ctx, last, updates)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Warn("[OrderService] Following order status failed:", "orderID", orderID, "err", err)
+				}
+				return
+			}
+			last = next
+		}
+	}()
+	return out, nil
+}
+
+// nextStatus waits for a status other than the last one.
+func (s *Service) nextStatus(ctx context.Context, last watcher.Update, updates <-chan watcher.Update) (watcher.Update, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case u := <-updates:
+			if u.Status == "" {
+				// notifications may have been missed, look at the row itself
+				o, err := s.Repo.GetOrder(ctx, last.OrderID)
+				if err != nil {
+					return last, err
+				}
+				u = watcher.Update{OrderID: o.ID, Status: o.Status, UpdatedAt: o.UpdatedAt}
+			}
+			if u.Status != last.Status {
+				return u, nil
+			}
+		}
+	}
+}
+
+// ListOrders returns a page of orders and the cursor of the next page, which is
+// empty on the last page.
+func (s *Service) ListOrders(ctx context.Context, f domain.OrderFilter) ([]domain.Order, string, error) {
+	if f.Limit <= 0 {
+		f.Limit = domain.DefaultListLimit
+	}
+	if f.Limit > domain.MaxListLimit {
+		f.Limit = domain.MaxListLimit
+	}
+	limit := f.Limit
+
+	// fetch one extra row to know whether there is a next page
+	f.Limit++
+	orders, err := s.Repo.ListOrders(ctx, f)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(orders) <= limit {
+		return orders, "", nil
+	}
+
+	orders = orders[:limit]
+	last := orders[limit-1]
+	return orders, domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode(), nil
+}
+
+// HandleSagaEvent drives the order saga with an event received from another service.
+// Illegal transitions are rejected and logged; the order keeps its current status.
+// Redelivered events are recognised by their event id and skipped.
+func (s *Service) HandleSagaEvent(ctx context.Context, orderID string, evt domain.Event, cause domain.Cause) {
+	var processed bool
+	step, err := retryOnConflict(ctx, orderID, func() (step domain.SagaStep, err error) {
+		step, processed, err = s.Repo.TransitionOrderOnce(ctx, orderID, evt, cause)
+		return step, err
+	})
+	if err == nil && !processed {
+		slog.Info("Order saga event already processed, skipping:", "orderID", orderID, "event", evt, "eventID", cause.EventID)
+		return
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			slog.Warn("Order saga event rejected:", "orderID", orderID, "event", evt, "status", step.FromStatus, "err", err)
+			return
+		}
+		slog.Error("Failed to apply order saga event:", "orderID", orderID, "event", evt, "err", err)
+		return
+	}
+	slog.Info("Order saga transition:", "orderID", orderID, "event", evt, "from", step.FromStatus, "to", step.ToStatus)
+}
+
+// CancelOrder cancels the order on behalf of the customer with id customerID.
+// The cancel is rejected with domain.ErrIllegalTransition when the current
+// status does not allow it, and with domain.ErrOrderNotFound when the order is
+// another customer's.
+func (s *Service) CancelOrder(ctx context.Context, orderID, customerID string) (domain.SagaStep, error) {
+	step, err := retryOnConflict(ctx, orderID, func() (domain.SagaStep, error) {
+		return s.Repo.CancelOrder(ctx, orderID, customerID)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			slog.Info("Order cancel rejected:", "orderID", orderID, "status", step.FromStatus)
+		}
+		return step, err
+	}
+	slog.Info("Order cancelled:", "orderID", orderID, "from", step.FromStatus, "to", step.ToStatus)
+	return step, nil
 }
 
-func (s *Service) UpdateOrderAwaitingPayment(ctx context.Context, orderID string) {
-	err := s.UpdateOrder(ctx, orderID, domain.StatusAwaitingPayment)
+// RefundOrder refunds the given lines of a paid order of the customer with id
+// customerID, or all of what is left of it without lines. The refund is
+// rejected with domain.ErrIllegalTransition when the order is not paid, with
+// domain.ErrInvalidRefund when the lines ask for more than is left and with
+// domain.ErrOrderNotFound when the order is another customer's.
+func (s *Service) RefundOrder(ctx context.Context, orderID, customerID string, lines []domain.RefundLine, reason string) (*domain.Refund, domain.SagaStep, error) {
+	var refund *domain.Refund
+	step, err := retryOnConflict(ctx, orderID, func() (step domain.SagaStep, err error) {
+		refund, step, err = s.Repo.RequestRefund(ctx, orderID, customerID, lines, reason)
+		return step, err
+	})
 	if err != nil {
-		// TODO: handle errors better, ex:
-		// - cron to deal with PENDINGs for long time
-		// - alerting
-		// - manual intervention list
-		// - fail them
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			slog.Info("Order refund rejected:", "orderID", orderID, "status", step.FromStatus)
+		}
+		return nil, step, err
 	}
+	slog.Info("Order refund requested:", "orderID", orderID, "refundID", refund.ID, "amount", refund.Amount, "full", refund.Full)
+	return refund, step, nil
+}
+
+// ListRefunds returns the refunds of an existing order, oldest first.
+func (s *Service) ListRefunds(ctx context.Context, orderID string) ([]domain.Refund, error) {
+	if _, err := s.Repo.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.Repo.ListRefunds(ctx, orderID)
+}
+
+// HandleRefundReply settles a refund with the reply of the payment service.
+// Redelivered replies are recognised by their event id and skipped.
+func (s *Service) HandleRefundReply(ctx context.Context, orderID, refundID string, succeeded bool, reason string, cause domain.Cause) {
+	var processed bool
+	step, err := retryOnConflict(ctx, orderID, func() (step domain.SagaStep, err error) {
+		step, processed, err = s.Repo.CompleteRefundOnce(ctx, refundID, succeeded, reason, cause)
+		return step, err
+	})
+	if err == nil && !processed {
+		slog.Info("Refund reply already processed, skipping:", "orderID", orderID, "refundID", refundID, "eventID", cause.EventID)
+		return
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			slog.Warn("Refund reply rejected:", "orderID", orderID, "refundID", refundID, "status", step.FromStatus, "err", err)
+			return
+		}
+		slog.Error("Failed to apply refund reply:", "orderID", orderID, "refundID", refundID, "err", err)
+		return
+	}
+	if step.Event == "" {
+		slog.Info("Refund already settled, skipping:", "orderID", orderID, "refundID", refundID)
+		return
+	}
+	slog.Info("Order saga transition:", "orderID", orderID, "event", step.Event, "from", step.FromStatus, "to", step.ToStatus, "refundID", refundID)
+}
 
-	ch, err := s.Sync.Pull(orderID)
+// HandleShipmentEvent records what the shipping service reported about the
+// shipment of an order and drives the saga with it. Redelivered events are
+// recognised by their event id and skipped.
+func (s *Service) HandleShipmentEvent(ctx context.Context, shipment domain.Shipment, evt domain.Event, cause domain.Cause) {
+	var processed bool
+	step, err := retryOnConflict(ctx, shipment.OrderID, func() (step domain.SagaStep, err error) {
+		step, processed, err = s.Repo.RecordShipmentOnce(ctx, shipment, evt, cause)
+		return step, err
+	})
+	if err == nil && !processed {
+		slog.Info("Shipment event already processed, skipping:", "orderID", shipment.OrderID, "event", evt, "eventID", cause.EventID)
+		return
+	}
 	if err != nil {
-		slog.Error("Failed to pull sync channel for order", "orderID", orderID, "err", err)
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			slog.Warn("Shipment event rejected, shipment recorded:", "orderID", shipment.OrderID, "event", evt, "status", step.FromStatus, "err", err)
+			return
+		}
+		slog.Error("Failed to apply shipment event:", "orderID", shipment.OrderID, "event", evt, "err", err)
 		return
 	}
+	slog.Info("Order saga transition:", "orderID", shipment.OrderID, "event", evt, "from", step.FromStatus, "to", step.ToStatus, "trackingNumber", shipment.TrackingNumber)
+}
 
-	// Wait for the channel to receive a status
-	ch <- sync.OK
+// ExpireStaleOrders moves orders that stayed in Pending or AwaitingPayment past
+// the configured deadlines into compensation. It returns how many were expired.
+func (s *Service) ExpireStaleOrders(ctx context.Context) (int, error) {
+	cfg := s.cfg.Order.Expiry
+	deadlines := []struct {
+		status  domain.Status
+		timeout time.Duration
+	}{
+		{domain.StatusPending, cfg.PendingTimeout},
+		{domain.StatusAwaitingPayment, cfg.PaymentTimeout},
+	}
 
-	// push ch to map with SUCCESS
-	slog.Info("InventoryReservationSucceeded:", "orderID", orderID)
+	expired := 0
+	for _, d := range deadlines {
+		if d.timeout <= 0 {
+			continue
+		}
+		ids, err := s.Repo.ListStaleOrders(ctx, d.status, time.Now().Add(-d.timeout), cfg.BatchSize)
+		if err != nil {
+			return expired, err
+		}
+		for _, id := range ids {
+			// the order may have moved on since it was listed, the saga rejects it then
+			step, err := retryOnConflict(ctx, id, func() (domain.SagaStep, error) {
+				return s.Repo.ExpireOrder(ctx, id)
+			})
+			if err != nil {
+				slog.Warn("Failed to expire order:", "orderID", id, "err", err)
+				continue
+			}
+			slog.Info("Order expired:", "orderID", id, "from", step.FromStatus, "to", step.ToStatus)
+			expired++
+		}
+	}
+	return expired, nil
 }
 
-func (s *Service) UpdateOrderPaid(ctx context.Context, orderID string) {
-	time.Sleep(time.Second * 5)
-	if err := s.UpdateOrder(ctx, orderID, domain.StatusPaid); err != nil {
-		// TODO: handle errors better
+// RedriveStuckOrders publishes the command again for orders that waited in
+// Compensating, Cancelling or Refunding past the reply timeout. It returns how
+// many were redriven.
+func (s *Service) RedriveStuckOrders(ctx context.Context) (int, error) {
+	cfg := s.cfg.Order.Expiry
+	if cfg.ReplyTimeout <= 0 {
+		return 0, nil
+	}
+
+	redriven := 0
+	for _, status := range []domain.Status{domain.StatusCompensating, domain.StatusCancelling, domain.StatusRefunding} {
+		ids, err := s.Repo.ListStaleOrders(ctx, status, time.Now().Add(-cfg.ReplyTimeout), cfg.BatchSize)
+		if err != nil {
+			return redriven, err
+		}
+		for _, id := range ids {
+			// the reply may have arrived since the order was listed, the saga rejects it then
+			step, err := retryOnConflict(ctx, id, func() (domain.SagaStep, error) {
+				return s.Repo.RedriveOrder(ctx, id)
+			})
+			if err != nil {
+				slog.Warn("Failed to redrive order:", "orderID", id, "err", err)
+				continue
+			}
+			slog.Info("Order redriven:", "orderID", id, "status", step.ToStatus)
+			redriven++
+		}
 	}
-	slog.Info("Order paid:", "orderID", orderID)
+	return redriven, nil
 }
 
-func (s *Service) UpdateOrderFailed(ctx context.Context, orderID string) {
-	if err := s.UpdateOrder(ctx, orderID, domain.StatusFailed); err != nil {
-		// TODO: handle errors better
+const (
+	// conflictAttempts bounds how often a write that lost a race is tried.
+	conflictAttempts = 5
+	// conflictBackoff is the pause before the second attempt; it grows linearly.
+	conflictBackoff = 20 * time.Millisecond
+)
+
+// retryOnConflict runs write again while it fails with
+// domain.ErrConcurrentModification, up to conflictAttempts times. write must
+// reload the order on every call, as the repository writes do, so that each
+// attempt decides on the latest version.
+func retryOnConflict[T any](ctx context.Context, orderID string, write func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := write()
+		if !errors.Is(err, domain.ErrConcurrentModification) || attempt == conflictAttempts {
+			return res, err
+		}
+		slog.Info("[OrderService] Order modified concurrently, retrying:", "orderID", orderID, "attempt", attempt)
+
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-time.After(time.Duration(attempt) * conflictBackoff):
+		}
 	}
-	slog.Info("Order failed:", "orderID", orderID)
 }
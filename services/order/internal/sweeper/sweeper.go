@@ -0,0 +1,72 @@
+package sweeper
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/services/order/internal/service"
+)
+
+// advisoryLockKey elects the single replica that sweeps at a given time.
+const advisoryLockKey int64 = 0x6f72646572 // "order"
+
+// Sweeper periodically expires orders stuck in Pending or AwaitingPayment,
+// redrives those stuck waiting for a compensation or refund reply and purges
+// idempotency keys past retention.
+type Sweeper struct {
+	DB       *db.DB
+	Service  *service.Service
+	Interval time.Duration
+}
+
+func New(db *db.DB, svc *service.Service, interval time.Duration) *Sweeper {
+	return &Sweeper{DB: db, Service: svc, Interval: interval}
+}
+
+func (s *Sweeper) Start(ctx context.Context) error {
+	if s.Interval <= 0 {
+		slog.Info("Order expiry sweeper disabled")
+		return nil
+	}
+
+	slog.Info("Order expiry sweeper started", "interval", s.Interval)
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	var expired, redriven int
+	var purged int64
+	leader, err := s.DB.TryAdvisoryLock(ctx, advisoryLockKey, func(ctx context.Context) error {
+		var err error
+		if expired, err = s.Service.ExpireStaleOrders(ctx); err != nil {
+			return err
+		}
+		if redriven, err = s.Service.RedriveStuckOrders(ctx); err != nil {
+			return err
+		}
+		purged, err = s.Service.PurgeIdempotencyKeys(ctx)
+		return err
+	})
+	if err != nil {
+		slog.Error("Order expiry sweep failed:", "err", err)
+		return
+	}
+	if !leader {
+		slog.Debug("Order expiry sweep skipped, another replica is sweeping")
+		return
+	}
+	if expired > 0 || redriven > 0 || purged > 0 {
+		slog.Info("Order expiry sweep finished", "expired", expired, "redriven", redriven, "purgedIdempotencyKeys", purged)
+	}
+}
@@ -0,0 +1,118 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/services/order/internal/domain"
+	"github.com/axmz/go-saga-microservices/services/order/internal/repository"
+)
+
+// Update is a status change of one order. An empty Status means notifications
+// may have been missed and the order should be re-read from the database.
+type Update struct {
+	OrderID   string
+	Status    domain.Status
+	UpdatedAt time.Time
+}
+
+// Watcher fans out order status notifications from Postgres to local subscribers.
+// Because the notifications go through the database, an update committed by any
+// replica reaches the subscribers of every replica.
+type Watcher struct {
+	DB *db.DB
+
+	mu   sync.Mutex
+	subs map[string]map[chan Update]struct{}
+}
+
+func New(db *db.DB) *Watcher {
+	return &Watcher{
+		DB:   db,
+		subs: make(map[string]map[chan Update]struct{}),
+	}
+}
+
+// Watch subscribes to status changes of the given order until stop is called.
+func (w *Watcher) Watch(orderID string) (<-chan Update, func()) {
+	ch := make(chan Update, 4)
+
+	w.mu.Lock()
+	if w.subs[orderID] == nil {
+		w.subs[orderID] = make(map[chan Update]struct{})
+	}
+	w.subs[orderID][ch] = struct{}{}
+	w.mu.Unlock()
+
+	stop := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subs[orderID], ch)
+		if len(w.subs[orderID]) == 0 {
+			delete(w.subs, orderID)
+		}
+	}
+	return ch, stop
+}
+
+func (w *Watcher) Start(ctx context.Context) error {
+	notifications, err := w.DB.Listen(ctx, repository.OrderStatusChannel)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Order status watcher started")
+	for n := range notifications {
+		if n.Reconnected {
+			slog.Warn("Order status watcher reconnected, resyncing subscribers")
+			w.resync()
+			continue
+		}
+
+		var sn repository.StatusNotification
+		if err := json.Unmarshal([]byte(n.Payload), &sn); err != nil {
+			slog.Warn("Failed to unmarshal order status notification:", "err", err)
+			continue
+		}
+		w.dispatch(Update{OrderID: sn.OrderID, Status: sn.Status, UpdatedAt: sn.UpdatedAt})
+	}
+	return ctx.Err()
+}
+
+func (w *Watcher) dispatch(u Update) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.subs[u.OrderID] {
+		send(ch, u)
+	}
+}
+
+func (w *Watcher) resync() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for orderID, chans := range w.subs {
+		for ch := range chans {
+			send(ch, Update{OrderID: orderID})
+		}
+	}
+}
+
+// send never blocks the watcher; a slow subscriber loses its oldest updates
+// rather than the latest one.
+func send(ch chan Update, u Update) {
+	for {
+		select {
+		case ch <- u:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS saga_steps;
+
+UPDATE orders SET status = 'Failed' WHERE status = 'Compensating';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Failed'));
@@ -0,0 +1,16 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Compensating', 'Failed'));
+
+CREATE TABLE IF NOT EXISTS saga_steps (
+    id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    accepted BOOLEAN NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_steps_order_id ON saga_steps (order_id, id);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_orders_status_updated_at ON orders (status, updated_at);
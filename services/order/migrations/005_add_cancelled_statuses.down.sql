@@ -0,0 +1,4 @@
+UPDATE orders SET status = 'Failed' WHERE status IN ('Cancelling', 'Cancelled');
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Compensating', 'Failed'));
@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Compensating', 'Failed', 'Cancelling', 'Cancelled'));
@@ -0,0 +1,19 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS item_ids VARCHAR(255) NOT NULL DEFAULT '';
+
+DO $$
+BEGIN
+    IF to_regclass('order_items') IS NOT NULL THEN
+        UPDATE orders o
+        SET item_ids = COALESCE((
+            SELECT string_agg(oi.sku, ',' ORDER BY oi.sku)
+            FROM order_items oi
+            WHERE oi.order_id = o.id
+        ), '');
+    END IF;
+END $$;
+
+ALTER TABLE orders ALTER COLUMN item_ids DROP DEFAULT;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS total;
+
+DROP TABLE IF EXISTS order_items;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id VARCHAR(36) NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    sku VARCHAR(100) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (unit_price >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    PRIMARY KEY (order_id, sku)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_sku ON order_items (sku);
+
+-- Backfill from the comma-joined item_ids. Prices were never stored, so old
+-- lines keep a zero unit price.
+INSERT INTO order_items (order_id, sku, quantity)
+SELECT o.id, s.sku, COUNT(*)
+FROM orders o
+CROSS JOIN LATERAL unnest(string_to_array(o.item_ids, ',')) AS s(sku)
+WHERE s.sku <> ''
+GROUP BY o.id, s.sku
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE orders DROP COLUMN IF EXISTS item_ids;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL,
+    event TEXT NOT NULL,
+    kafka_topic TEXT,
+    kafka_partition INTEGER,
+    kafka_offset BIGINT,
+    outbox_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history (order_id, created_at, id);
+
+-- Backfill from the saga log; the cause of those changes was never recorded.
+INSERT INTO order_status_history (order_id, from_status, to_status, event, created_at)
+SELECT order_id, from_status, to_status, event, created_at
+FROM saga_steps
+WHERE accepted AND from_status <> to_status
+ORDER BY id;
@@ -0,0 +1,13 @@
+CREATE INDEX IF NOT EXISTS idx_outbox_published_created_at ON outbox (created_at) WHERE published_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS outbox_archive (
+    id UUID PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    headers JSONB DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS orders DROP COLUMN IF EXISTS subtotal;
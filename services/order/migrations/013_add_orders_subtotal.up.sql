@@ -0,0 +1,4 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+-- Orders so far had no costs on top of their lines.
+UPDATE orders SET subtotal = total WHERE subtotal = 0;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_orders_customer_created_at;
+ALTER TABLE IF EXISTS orders DROP COLUMN IF EXISTS shipping_address;
+ALTER TABLE IF EXISTS orders DROP COLUMN IF EXISTS email;
+ALTER TABLE IF EXISTS orders DROP COLUMN IF EXISTS customer_id;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS refund_items;
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE IF EXISTS order_items DROP COLUMN IF EXISTS refunded_quantity;
+
+UPDATE orders SET status = 'Paid' WHERE status IN ('Refunding', 'Refunded');
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Compensating', 'Failed', 'Cancelling', 'Cancelled'));
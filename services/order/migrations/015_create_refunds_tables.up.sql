@@ -0,0 +1,29 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Compensating', 'Failed', 'Cancelling', 'Cancelled', 'Refunding', 'Refunded'));
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS refunded_quantity INTEGER NOT NULL DEFAULT 0
+    CHECK (refunded_quantity >= 0 AND refunded_quantity <= quantity);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id VARCHAR(36) PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('Requested', 'Succeeded', 'Failed')),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    full_refund BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_order_created_at ON refunds (order_id, created_at);
+
+CREATE TABLE IF NOT EXISTS refund_items (
+    refund_id VARCHAR(36) NOT NULL REFERENCES refunds (id) ON DELETE CASCADE,
+    sku VARCHAR(100) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (refund_id, sku)
+);
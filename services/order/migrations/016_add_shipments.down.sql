@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS shipments;
+
+UPDATE orders SET status = 'Paid' WHERE status IN ('Shipped', 'Delivered');
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Compensating', 'Failed', 'Cancelling', 'Cancelled', 'Refunding', 'Refunded'));
@@ -0,0 +1,14 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('Pending', 'AwaitingPayment', 'Paid', 'Compensating', 'Failed', 'Cancelling', 'Cancelled', 'Refunding', 'Refunded', 'Shipped', 'Delivered'));
+
+-- The latest the shipping service reported about an order's shipment
+CREATE TABLE IF NOT EXISTS shipments (
+    order_id VARCHAR(36) PRIMARY KEY REFERENCES orders (id) ON DELETE CASCADE,
+    shipment_id VARCHAR(36) NOT NULL,
+    carrier VARCHAR(50) NOT NULL,
+    tracking_number VARCHAR(100) NOT NULL,
+    tracking_url TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL CHECK (status IN ('Created', 'Dispatched', 'Delivered')),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
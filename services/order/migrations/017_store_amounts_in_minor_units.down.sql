@@ -0,0 +1,12 @@
+ALTER TABLE refund_items ALTER COLUMN amount_minor TYPE NUMERIC(12,2) USING amount_minor / 100.0;
+ALTER TABLE refund_items RENAME COLUMN amount_minor TO amount;
+ALTER TABLE refunds ALTER COLUMN amount_minor TYPE NUMERIC(12,2) USING amount_minor / 100.0;
+ALTER TABLE refunds RENAME COLUMN amount_minor TO amount;
+
+ALTER TABLE orders ALTER COLUMN total_minor TYPE NUMERIC(12,2) USING total_minor / 100.0;
+ALTER TABLE orders RENAME COLUMN total_minor TO total;
+ALTER TABLE orders ALTER COLUMN subtotal_minor TYPE NUMERIC(12,2) USING subtotal_minor / 100.0;
+ALTER TABLE orders RENAME COLUMN subtotal_minor TO subtotal;
+
+ALTER TABLE order_items ALTER COLUMN unit_price_minor TYPE NUMERIC(10,2) USING unit_price_minor / 100.0;
+ALTER TABLE order_items RENAME COLUMN unit_price_minor TO unit_price;
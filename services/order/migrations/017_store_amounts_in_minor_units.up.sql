@@ -0,0 +1,14 @@
+-- Amounts are kept as whole minor units of the row's currency, cents for USD,
+-- so that adding up lines never drifts from what was charged.
+ALTER TABLE order_items RENAME COLUMN unit_price TO unit_price_minor;
+ALTER TABLE order_items ALTER COLUMN unit_price_minor TYPE BIGINT USING ROUND(unit_price_minor * 100);
+
+ALTER TABLE orders RENAME COLUMN subtotal TO subtotal_minor;
+ALTER TABLE orders ALTER COLUMN subtotal_minor TYPE BIGINT USING ROUND(subtotal_minor * 100);
+ALTER TABLE orders RENAME COLUMN total TO total_minor;
+ALTER TABLE orders ALTER COLUMN total_minor TYPE BIGINT USING ROUND(total_minor * 100);
+
+ALTER TABLE refunds RENAME COLUMN amount TO amount_minor;
+ALTER TABLE refunds ALTER COLUMN amount_minor TYPE BIGINT USING ROUND(amount_minor * 100);
+ALTER TABLE refund_items RENAME COLUMN amount TO amount_minor;
+ALTER TABLE refund_items ALTER COLUMN amount_minor TYPE BIGINT USING ROUND(amount_minor * 100);
@@ -9,6 +9,8 @@ import (
 
 	"github.com/axmz/go-graceful"
 	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/grpc"
 	"github.com/axmz/go-saga-microservices/lib/adapter/http"
 	"github.com/axmz/go-saga-microservices/lib/adapter/kafka"
 	"github.com/axmz/go-saga-microservices/lib/logger"
@@ -34,6 +36,12 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 
+	// connect to database
+	db, err := db.Connect(db.Config(cfg.Payment.DB))
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
 	// initialize kafka
 	kafka, err := kafka.Init(kafka.Config(cfg.Payment.Kafka))
 	if err != nil {
@@ -48,8 +56,15 @@ func main() {
 		cancel()
 	}
 
+	// initialize grpc server
+	gsrv, err := grpc.NewServer(grpc.Config(cfg.Payment.GRPC))
+	if err != nil {
+		slog.Error("Failed to initialize gRPC server:", "err", err)
+		cancel()
+	}
+
 	// setup app
-	app, err := app.SetupApp(cfg, logger, srv, kafka)
+	app, err := app.SetupApp(cfg, logger, db, srv, gsrv, kafka)
 	if err != nil {
 		slog.Error("Failed to initialize app:", "err", err)
 		cancel()
@@ -65,10 +80,32 @@ func main() {
 		}
 	}()
 
+	// gRPC server
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.GRPC.Run(); err != nil {
+			slog.Error("gRPC server terminated:", "err", err)
+			cancel()
+		}
+	}()
+
+	// Kafka consumer
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.Consumer.Start(ctx); err != nil {
+			slog.Error("Kafka consumer group terminated:", "err", err)
+			cancel()
+		}
+	}()
+
 	// Wait for shutdown signal or context cancellation
 	<-graceful.Shutdown(ctx, app.Config.GracefulTimeout, map[string]graceful.Operation{
 		"kafka":       app.Kafka.Shutdown,
+		"database":    app.DB.Shutdown,
 		"http-server": app.HTTP.Shutdown,
+		"grpc-server": app.GRPC.Shutdown,
 	})
 
 	wg.Wait()
@@ -4,37 +4,54 @@ import (
 	"log/slog"
 
 	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/grpc"
 	"github.com/axmz/go-saga-microservices/lib/adapter/http"
 	"github.com/axmz/go-saga-microservices/lib/adapter/kafka"
+	"github.com/axmz/go-saga-microservices/payment-service/internal/consumer"
 	"github.com/axmz/go-saga-microservices/payment-service/internal/handler"
 	"github.com/axmz/go-saga-microservices/payment-service/internal/publisher"
+	"github.com/axmz/go-saga-microservices/payment-service/internal/repository"
 	"github.com/axmz/go-saga-microservices/payment-service/internal/router"
 	"github.com/axmz/go-saga-microservices/payment-service/internal/service"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
 )
 
 type App struct {
 	Config    *config.Config
+	Consumer  *consumer.Consumer
+	DB        *db.DB
+	GRPC      *grpc.Server
 	HTTP      *http.Server
 	Kafka     *kafka.Broker
 	Log       *slog.Logger
 	Publisher *publisher.Publisher
+	Repo      *repository.Repository
 	Services  *service.Service
 }
 
 func SetupApp(
 	cfg *config.Config,
 	log *slog.Logger,
+	db *db.DB,
 	srv *http.Server,
+	gsrv *grpc.Server,
 	kfk *kafka.Broker,
 ) (*App, error) {
+	rep := repository.New(db)
 	pub := publisher.New(kfk.Writer)
-	svc := service.New(pub)
+	svc := service.New(rep, pub)
 	han := handler.New(svc)
+	con := consumer.New(kfk, han)
 	mux := router.New(han)
 	srv.Router.Handler = http.LoggingMiddleware(mux)
+	rpc.RegisterPaymentServiceServer(gsrv.Router, handler.NewGRPCServer(svc))
 
 	app := &App{
 		Config:   cfg,
+		Consumer: con,
+		DB:       db,
+		GRPC:     gsrv,
 		HTTP:     srv,
 		Kafka:    kfk,
 		Log:      log,
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	// StatusRefunded is a succeeded payment given back in full, after the order
+	// was cancelled or refunded.
+	StatusRefunded Status = "refunded"
+	// StatusVoided marks an order cancelled or expired before it was paid;
+	// later payments are refused.
+	StatusVoided Status = "voided"
+)
+
+// ErrOrderCancelled refuses a payment for an order that was cancelled or
+// expired before it was paid.
+var ErrOrderCancelled = errors.New("order cancelled")
+
+// ErrUnknownOrder refuses a payment for an order whose OrderCreated event has
+// not reached payment yet, so its total is not known.
+var ErrUnknownOrder = errors.New("unknown order")
+
+type ErrUnknownOrderWithID struct {
+	OrderID string
+}
+
+func (e *ErrUnknownOrderWithID) Error() string {
+	return fmt.Sprintf("unknown order: %s", e.OrderID)
+}
+
+func (e *ErrUnknownOrderWithID) Unwrap() error {
+	return ErrUnknownOrder
+}
+
+func NewErrUnknownOrder(orderID string) error {
+	return &ErrUnknownOrderWithID{OrderID: orderID}
+}
+
+// ErrAmountMismatch refuses a charge that is not the order total, or a refund
+// that would give back more than was charged.
+var ErrAmountMismatch = errors.New("amount does not match the order total")
+
+type ErrAmountMismatchWithReason struct {
+	OrderID string
+	Reason  string
+}
+
+func (e *ErrAmountMismatchWithReason) Error() string {
+	return fmt.Sprintf("amount does not match the total of order %s: %s", e.OrderID, e.Reason)
+}
+
+func (e *ErrAmountMismatchWithReason) Unwrap() error {
+	return ErrAmountMismatch
+}
+
+func NewErrAmountMismatch(orderID, reason string) error {
+	return &ErrAmountMismatchWithReason{OrderID: orderID, Reason: reason}
+}
+
+// Compensated returns the status a payment in status s ends in once its order
+// is cancelled or expired: a succeeded payment is refunded, a failed one is
+// voided so that later attempts are refused, and a refunded or voided one
+// stays as it is.
+func (s Status) Compensated() Status {
+	switch s {
+	case StatusSucceeded:
+		return StatusRefunded
+	case StatusFailed:
+		return StatusVoided
+	default:
+		return s
+	}
+}
+
+type Payment struct {
+	OrderID   string    `json:"order_id"`
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrderTotal is what an order costs as priced by the order service when it was
+// created, in minor units of Currency.
+type OrderTotal struct {
+	OrderID  string
+	Amount   int64
+	Currency string
+}
+
+// CheckCharge fails with ErrAmountMismatch unless amount in currency is the
+// order total.
+func (t OrderTotal) CheckCharge(amount int64, currency string) error {
+	if amount != t.Amount || currency != t.Currency {
+		return NewErrAmountMismatch(t.OrderID, fmt.Sprintf("charged %d %s, total is %d %s", amount, currency, t.Amount, t.Currency))
+	}
+	return nil
+}
+
+// CheckRefund fails with ErrAmountMismatch when refund, on top of the refunded
+// amount already given back, would exceed the order total or is in another
+// currency.
+func (t OrderTotal) CheckRefund(refunded int64, refund Refund) error {
+	if refund.Currency != t.Currency {
+		return NewErrAmountMismatch(t.OrderID, fmt.Sprintf("refund in %s, total is in %s", refund.Currency, t.Currency))
+	}
+	if refund.Amount < 0 || refunded+refund.Amount > t.Amount {
+		return NewErrAmountMismatch(t.OrderID, fmt.Sprintf("refunding %d on top of %d, total is %d", refund.Amount, refunded, t.Amount))
+	}
+	return nil
+}
+
+// Refund gives back part or all of a succeeded payment. Its status is either
+// StatusSucceeded or StatusFailed, with Reason telling why it failed. Amount
+// is in minor units of Currency.
+type Refund struct {
+	ID        string    `json:"id"`
+	OrderID   string    `json:"order_id"`
+	Amount    int64     `json:"amount_minor"`
+	Currency  string    `json:"currency"`
+	Full      bool      `json:"full"`
+	Status    Status    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
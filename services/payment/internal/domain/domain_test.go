@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompensated(t *testing.T) {
+	tests := []struct {
+		name string
+		from Status
+		want Status
+	}{
+		// the customer paid after the sweeper expired the order, the money goes back
+		{"payment succeeded after expiry", StatusSucceeded, StatusRefunded},
+		{"payment failed before expiry", StatusFailed, StatusVoided},
+		{"already refunded", StatusRefunded, StatusRefunded},
+		{"already voided", StatusVoided, StatusVoided},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.from.Compensated(); got != tt.want {
+				t.Errorf("%s.Compensated() = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRefund(t *testing.T) {
+	total := OrderTotal{OrderID: "o1", Amount: 5000, Currency: "USD"}
+	tests := []struct {
+		name     string
+		refunded int64
+		refund   Refund
+		wantErr  bool
+	}{
+		{"partial", 0, Refund{Amount: 1999, Currency: "USD"}, false},
+		{"rest of the order", 1999, Refund{Amount: 3001, Currency: "USD"}, false},
+		{"more than is left", 1999, Refund{Amount: 3002, Currency: "USD"}, true},
+		{"other currency", 0, Refund{Amount: 1999, Currency: "EUR"}, true},
+		{"negative", 0, Refund{Amount: -1, Currency: "USD"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := total.CheckRefund(tt.refunded, tt.refund)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckRefund(%d, %+v) = %v, want error %v", tt.refunded, tt.refund, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrAmountMismatch) {
+				t.Errorf("CheckRefund(%d, %+v) = %v, want ErrAmountMismatch", tt.refunded, tt.refund, err)
+			}
+		})
+	}
+}
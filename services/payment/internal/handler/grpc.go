@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/axmz/go-saga-microservices/payment-service/internal/domain"
+	"github.com/axmz/go-saga-microservices/payment-service/internal/service"
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer serves the PaymentService gRPC API on top of the same service as
+// the HTTP handlers.
+type GRPCServer struct {
+	rpc.UnimplementedPaymentServiceServer
+	Service *service.Service
+}
+
+func NewGRPCServer(service *service.Service) *GRPCServer {
+	return &GRPCServer{
+		Service: service,
+	}
+}
+
+func (s *GRPCServer) PaymentSuccess(ctx context.Context, req *httppb.PaymentSuccessRequest) (*httppb.PaymentSuccessResponse, error) {
+	if err := s.Service.PaymentSuccess(ctx, req.OrderId, req.AmountMinor, req.Currency); err != nil {
+		return nil, paymentStatusError(err, "PaymentSuccess", req.OrderId)
+	}
+
+	slog.Info("PaymentSuccess processed", "orderId", req.OrderId)
+	return &httppb.PaymentSuccessResponse{Success: true}, nil
+}
+
+func (s *GRPCServer) PaymentFail(ctx context.Context, req *httppb.PaymentFailRequest) (*httppb.PaymentFailResponse, error) {
+	if err := s.Service.PaymentFail(ctx, req.OrderId); err != nil {
+		return nil, paymentStatusError(err, "PaymentFail", req.OrderId)
+	}
+
+	slog.Info("PaymentFail processed", "orderId", req.OrderId)
+	return &httppb.PaymentFailResponse{Success: true}, nil
+}
+
+// paymentStatusError answers a payment of a cancelled order with
+// FailedPrecondition, the 409 Conflict of the HTTP API, one of an unknown order
+// with NotFound and a charge that is not the order total with InvalidArgument.
+func paymentStatusError(err error, method, orderID string) error {
+	switch {
+	case errors.Is(err, domain.ErrOrderCancelled):
+		slog.Warn(method+" for cancelled order", "orderId", orderID)
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrUnknownOrder):
+		slog.Warn(method+" for unknown order", "orderId", orderID)
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrAmountMismatch):
+		slog.Warn(method+" amount mismatch", "orderId", orderID, "err", err)
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	slog.Error(method+" service error", "orderId", orderID, "err", err)
+	return status.Error(codes.Internal, "internal server error")
+}
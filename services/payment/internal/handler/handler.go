@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 
 	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
+	"github.com/axmz/go-saga-microservices/payment-service/internal/domain"
 	"github.com/axmz/go-saga-microservices/payment-service/internal/service"
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
 	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -30,7 +36,22 @@ func (h *Handler) PaymentSuccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.Service.PaymentSuccess(r.Context(), req.OrderId); err != nil {
+	if err := h.Service.PaymentSuccess(r.Context(), req.OrderId, req.AmountMinor, req.Currency); err != nil {
+		if errors.Is(err, domain.ErrOrderCancelled) {
+			slog.Warn("PaymentSuccess for cancelled order", "orderId", req.OrderId)
+			httputils.ErrorConflict(w, err)
+			return
+		}
+		if errors.Is(err, domain.ErrUnknownOrder) {
+			slog.Warn("PaymentSuccess for unknown order", "orderId", req.OrderId)
+			httputils.ErrorNotFound(w, err)
+			return
+		}
+		if errors.Is(err, domain.ErrAmountMismatch) {
+			slog.Warn("PaymentSuccess amount mismatch", "orderId", req.OrderId, "err", err)
+			httputils.ErrorBadRequest(w, err)
+			return
+		}
 		slog.Error("PaymentSuccess service error", "orderId", req.OrderId, "err", err)
 		httputils.ErrorInternal(w, err)
 		return
@@ -50,6 +71,11 @@ func (h *Handler) PaymentFail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.Service.PaymentFail(r.Context(), req.OrderId); err != nil {
+		if errors.Is(err, domain.ErrOrderCancelled) {
+			slog.Warn("PaymentFail for cancelled order", "orderId", req.OrderId)
+			httputils.ErrorConflict(w, err)
+			return
+		}
 		slog.Error("PaymentFail service error", "orderId", req.OrderId, "err", err)
 		httputils.ErrorInternal(w, err)
 		return
@@ -59,6 +85,41 @@ func (h *Handler) PaymentFail(w http.ResponseWriter, r *http.Request) {
 	h.respondWithPaymentFail(w)
 }
 
+func (h *Handler) OrderEvents(ctx context.Context, event kafka.Message) error {
+	envelope, err := codec.Decode[*events.OrderEventEnvelope](event)
+	if err != nil {
+		return err
+	}
+	switch evt := envelope.Event.(type) {
+	case *events.OrderEventEnvelope_OrderCreated:
+		slog.Info("Order event: created", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderCreated.Id)
+		return h.Service.RecordOrderTotal(ctx, domain.OrderTotal{
+			OrderID:  evt.OrderCreated.Id,
+			Amount:   evt.OrderCreated.TotalMinor,
+			Currency: evt.OrderCreated.Currency,
+		})
+	case *events.OrderEventEnvelope_OrderCancelled:
+		slog.Info("Order event: cancelled", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderCancelled.Id)
+		h.Service.CancelPayment(ctx, evt.OrderCancelled.Id)
+	case *events.OrderEventEnvelope_OrderExpired:
+		slog.Info("Order event: expired", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderExpired.Id)
+		h.Service.CancelPayment(ctx, evt.OrderExpired.Id)
+	case *events.OrderEventEnvelope_RefundRequested:
+		req := evt.RefundRequested
+		slog.Info("Order event: refund requested", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", req.Id, "refundId", req.RefundId)
+		h.Service.RefundPayment(ctx, domain.Refund{
+			ID:       req.RefundId,
+			OrderID:  req.Id,
+			Amount:   req.AmountMinor,
+			Currency: req.Currency,
+			Full:     req.Full,
+		})
+	default:
+		// other order events need nothing from payment
+	}
+	return nil
+}
+
 // REQ PROCESSING
 func (h *Handler) parseProtoBody(r *http.Request, msg proto.Message) error {
 	body, err := io.ReadAll(r.Body)
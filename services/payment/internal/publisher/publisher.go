@@ -4,9 +4,11 @@ import (
 	"context"
 	"log"
 
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
+	"github.com/axmz/go-saga-microservices/payment-service/internal/domain"
 	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 )
 
 type Publisher struct {
@@ -21,6 +23,7 @@ func (k *Publisher) PublishPaymentSucceededEvent(orderID string) error {
 	log.Printf("[Payment Service] Publishing payment success event for order: %s, status: %s", orderID, "success")
 
 	event := &events.PaymentEventEnvelope{
+		EventId: uuid.New().String(),
 		Event: &events.PaymentEventEnvelope_PaymentSucceeded{
 			PaymentSucceeded: &events.PaymentSucceeded{
 				Id: orderID,
@@ -28,21 +31,14 @@ func (k *Publisher) PublishPaymentSucceededEvent(orderID string) error {
 		},
 	}
 
-	value, err := proto.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	return k.Writer.WriteMessages(context.Background(), kafka.Message{
-		Key:   []byte(orderID),
-		Value: value,
-	})
+	return k.publish(orderID, event)
 }
 
 func (k *Publisher) PublishPaymentFailedEvent(orderID string) error {
 	log.Printf("[Payment Service] Publishing payment failed event for order: %s, status: %s", orderID, "failed")
 
 	event := &events.PaymentEventEnvelope{
+		EventId: uuid.New().String(),
 		Event: &events.PaymentEventEnvelope_PaymentFailed{
 			PaymentFailed: &events.PaymentFailed{
 				Id: orderID,
@@ -50,13 +46,63 @@ func (k *Publisher) PublishPaymentFailedEvent(orderID string) error {
 		},
 	}
 
-	value, err := proto.Marshal(event)
+	return k.publish(orderID, event)
+}
+
+func (k *Publisher) PublishPaymentRefundedEvent(orderID string) error {
+	log.Printf("[Payment Service] Publishing payment refunded event for order: %s, status: %s", orderID, "refunded")
+
+	event := &events.PaymentEventEnvelope{
+		EventId: uuid.New().String(),
+		Event: &events.PaymentEventEnvelope_PaymentRefunded{
+			PaymentRefunded: &events.PaymentRefunded{
+				Id: orderID,
+			},
+		},
+	}
+
+	return k.publish(orderID, event)
+}
+
+func (k *Publisher) PublishRefundSucceededEvent(refund domain.Refund) error {
+	log.Printf("[Payment Service] Publishing refund succeeded event for order: %s, refund: %s", refund.OrderID, refund.ID)
+
+	event := &events.PaymentEventEnvelope{
+		EventId: uuid.New().String(),
+		Event: &events.PaymentEventEnvelope_RefundSucceeded{
+			RefundSucceeded: &events.RefundSucceeded{
+				Id:          refund.OrderID,
+				RefundId:    refund.ID,
+				AmountMinor: refund.Amount,
+				Currency:    refund.Currency,
+			},
+		},
+	}
+
+	return k.publish(refund.OrderID, event)
+}
+
+func (k *Publisher) PublishRefundFailedEvent(refund domain.Refund) error {
+	log.Printf("[Payment Service] Publishing refund failed event for order: %s, refund: %s", refund.OrderID, refund.ID)
+
+	event := &events.PaymentEventEnvelope{
+		EventId: uuid.New().String(),
+		Event: &events.PaymentEventEnvelope_RefundFailed{
+			RefundFailed: &events.RefundFailed{
+				Id:       refund.OrderID,
+				RefundId: refund.ID,
+				Reason:   refund.Reason,
+			},
+		},
+	}
+
+	return k.publish(refund.OrderID, event)
+}
+
+func (k *Publisher) publish(orderID string, event *events.PaymentEventEnvelope) error {
+	msg, err := codec.Encode(orderID, event)
 	if err != nil {
 		return err
 	}
-
-	return k.Writer.WriteMessages(context.Background(), kafka.Message{
-		Key:   []byte(orderID),
-		Value: value,
-	})
+	return k.Writer.WriteMessages(context.Background(), msg)
 }
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/payment-service/internal/domain"
+)
+
+type Repository struct {
+	DB *db.DB
+}
+
+func New(db *db.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+// RecordOrderTotal remembers the total of a created order. A redelivered event
+// changes nothing, the total of an order never changes.
+func (r *Repository) RecordOrderTotal(ctx context.Context, total domain.OrderTotal) error {
+	_, err := r.DB.GetConn().ExecContext(ctx, `
+		INSERT INTO order_totals (order_id, total_minor, currency)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (order_id) DO NOTHING
+	`, total.OrderID, total.Amount, total.Currency)
+	return err
+}
+
+// GetOrderTotal returns the total of an order, or domain.ErrUnknownOrder when
+// its OrderCreated event was not consumed yet.
+func (r *Repository) GetOrderTotal(ctx context.Context, orderID string) (domain.OrderTotal, error) {
+	return scanOrderTotal(r.DB.GetConn().QueryRowContext(ctx, selectOrderTotal, orderID), orderID)
+}
+
+const selectOrderTotal = `SELECT total_minor, currency FROM order_totals WHERE order_id = $1`
+
+func scanOrderTotal(row *sql.Row, orderID string) (domain.OrderTotal, error) {
+	total := domain.OrderTotal{OrderID: orderID}
+	err := row.Scan(&total.Amount, &total.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return total, domain.NewErrUnknownOrder(orderID)
+	}
+	return total, err
+}
+
+// RecordPayment stores the outcome of a payment attempt. Attempts for orders
+// that were cancelled or expired in the meantime are refused with
+// domain.ErrOrderCancelled.
+func (r *Repository) RecordPayment(ctx context.Context, orderID string, status domain.Status) error {
+	const query = `
+		INSERT INTO payments (order_id, status)
+		VALUES ($1, $2)
+		ON CONFLICT (order_id) DO UPDATE
+		SET status = EXCLUDED.status, updated_at = now()
+		WHERE payments.status NOT IN ($3, $4)
+	`
+	res, err := r.DB.GetConn().ExecContext(ctx, query, orderID, status, domain.StatusRefunded, domain.StatusVoided)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrOrderCancelled
+	}
+	return nil
+}
+
+// CancelPayment settles the payment of a cancelled or expired order: a
+// succeeded payment is refunded, anything else is voided so that later
+// attempts are refused. It returns the status before and after.
+func (r *Repository) CancelPayment(ctx context.Context, orderID string) (domain.Status, domain.Status, error) {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	// make sure there is a row to lock, a payment racing the cancel then waits for us
+	res, err := tx.ExecContext(ctx, `INSERT INTO payments (order_id, status) VALUES ($1, $2) ON CONFLICT (order_id) DO NOTHING`, orderID, domain.StatusVoided)
+	if err != nil {
+		_ = tx.Rollback()
+		return "", "", err
+	}
+	if rows, _ := res.RowsAffected(); rows == 1 {
+		return "", domain.StatusVoided, tx.Commit()
+	}
+
+	var from domain.Status
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM payments WHERE order_id = $1 FOR UPDATE`, orderID).Scan(&from); err != nil {
+		_ = tx.Rollback()
+		return "", "", err
+	}
+
+	to := from.Compensated()
+
+	if to != from {
+		if _, err := tx.ExecContext(ctx, `UPDATE payments SET status = $1, updated_at = now() WHERE order_id = $2`, to, orderID); err != nil {
+			_ = tx.Rollback()
+			return from, from, err
+		}
+	}
+	return from, to, tx.Commit()
+}
+
+// RefundPayment records the refund asked for by the order service. It only
+// succeeds for a succeeded payment and as long as the refunds of the order add
+// up to no more than its total. The payment counts as refunded once the refund
+// is full. A refund that was recorded before is returned as it was, so that a
+// redelivered request gets the same answer.
+func (r *Repository) RefundPayment(ctx context.Context, refund domain.Refund) (domain.Refund, error) {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return refund, err
+	}
+
+	// lock the payment first, so that requests for the same refund queue up
+	var status domain.Status
+	err = tx.QueryRowContext(ctx, `SELECT status FROM payments WHERE order_id = $1 FOR UPDATE`, refund.OrderID).Scan(&status)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		_ = tx.Rollback()
+		return refund, err
+	}
+
+	var stored domain.Refund
+	err = tx.QueryRowContext(ctx, `
+		SELECT refund_id, order_id, amount_minor, currency, full_refund, status, reason, created_at
+		FROM refunds
+		WHERE refund_id = $1
+	`, refund.ID).Scan(&stored.ID, &stored.OrderID, &stored.Amount, &stored.Currency, &stored.Full, &stored.Status, &stored.Reason, &stored.CreatedAt)
+	if err == nil {
+		return stored, tx.Commit()
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		_ = tx.Rollback()
+		return refund, err
+	}
+
+	refund.Status = domain.StatusSucceeded
+	if status != domain.StatusSucceeded {
+		refund.Status = domain.StatusFailed
+		refund.Reason = fmt.Sprintf("payment of order %s is not refundable", refund.OrderID)
+		if status != "" {
+			refund.Reason += fmt.Sprintf(", it is %s", status)
+		}
+	} else if err := r.checkRefundTx(ctx, tx, refund); err != nil {
+		if !errors.Is(err, domain.ErrAmountMismatch) && !errors.Is(err, domain.ErrUnknownOrder) {
+			_ = tx.Rollback()
+			return refund, err
+		}
+		refund.Status = domain.StatusFailed
+		refund.Reason = err.Error()
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO refunds (refund_id, order_id, amount_minor, currency, full_refund, status, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`, refund.ID, refund.OrderID, refund.Amount, refund.Currency, refund.Full, refund.Status, refund.Reason).Scan(&refund.CreatedAt)
+	if err != nil {
+		_ = tx.Rollback()
+		return refund, err
+	}
+
+	if refund.Status == domain.StatusSucceeded && refund.Full {
+		if _, err := tx.ExecContext(ctx, `UPDATE payments SET status = $1, updated_at = now() WHERE order_id = $2`, domain.StatusRefunded, refund.OrderID); err != nil {
+			_ = tx.Rollback()
+			return refund, err
+		}
+	}
+	return refund, tx.Commit()
+}
+
+// checkRefundTx checks refund against the order total and the refunds that
+// already succeeded for the order.
+func (r *Repository) checkRefundTx(ctx context.Context, tx *sql.Tx, refund domain.Refund) error {
+	total, err := scanOrderTotal(tx.QueryRowContext(ctx, selectOrderTotal, refund.OrderID), refund.OrderID)
+	if err != nil {
+		return err
+	}
+	var refunded int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount_minor), 0)
+		FROM refunds
+		WHERE order_id = $1 AND status = $2
+	`, refund.OrderID, domain.StatusSucceeded).Scan(&refunded)
+	if err != nil {
+		return err
+	}
+	return total.CheckRefund(refunded, refund)
+}
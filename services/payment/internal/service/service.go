@@ -2,24 +2,90 @@ package service
 
 import (
 	"context"
+	"log/slog"
 
+	"github.com/axmz/go-saga-microservices/payment-service/internal/domain"
 	"github.com/axmz/go-saga-microservices/payment-service/internal/publisher"
+	"github.com/axmz/go-saga-microservices/payment-service/internal/repository"
 )
 
 type Service struct {
+	Repo  *repository.Repository
 	Kafka *publisher.Publisher
 }
 
-func New(kafka *publisher.Publisher) *Service {
+func New(repo *repository.Repository, kafka *publisher.Publisher) *Service {
 	return &Service{
+		Repo:  repo,
 		Kafka: kafka,
 	}
 }
 
-func (s *Service) PaymentSuccess(ctx context.Context, orderID string) error {
+// RecordOrderTotal remembers the total of a created order, which later charges
+// and refunds are checked against.
+func (s *Service) RecordOrderTotal(ctx context.Context, total domain.OrderTotal) error {
+	return s.Repo.RecordOrderTotal(ctx, total)
+}
+
+// PaymentSuccess records that amount in currency was charged for the order.
+// A charge that is not the order total fails with domain.ErrAmountMismatch,
+// one for an order payment has not heard of with domain.ErrUnknownOrder.
+func (s *Service) PaymentSuccess(ctx context.Context, orderID string, amount int64, currency string) error {
+	total, err := s.Repo.GetOrderTotal(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := total.CheckCharge(amount, currency); err != nil {
+		return err
+	}
+	if err := s.Repo.RecordPayment(ctx, orderID, domain.StatusSucceeded); err != nil {
+		return err
+	}
 	return s.Kafka.PublishPaymentSucceededEvent(orderID)
 }
 
 func (s *Service) PaymentFail(ctx context.Context, orderID string) error {
+	if err := s.Repo.RecordPayment(ctx, orderID, domain.StatusFailed); err != nil {
+		return err
+	}
 	return s.Kafka.PublishPaymentFailedEvent(orderID)
 }
+
+// CancelPayment compensates the payment of a cancelled or expired order: it
+// voids the order for future payments and refunds a payment that already
+// succeeded, such as one that arrived after the order expired.
+func (s *Service) CancelPayment(ctx context.Context, orderID string) {
+	from, to, err := s.Repo.CancelPayment(ctx, orderID)
+	if err != nil {
+		slog.Error("Failed to cancel payment", "orderID", orderID, "err", err)
+		return
+	}
+	slog.Info("Payment cancelled", "orderID", orderID, "from", from, "to", to)
+
+	if from == domain.StatusSucceeded && to == domain.StatusRefunded {
+		if err := s.Kafka.PublishPaymentRefundedEvent(orderID); err != nil {
+			slog.Error("Failed to publish payment refunded event", "orderID", orderID, "err", err)
+		}
+	}
+}
+
+// RefundPayment gives back the amount of a refund requested by the order
+// service and replies with its outcome. A redelivered request is answered
+// again with the outcome recorded the first time.
+func (s *Service) RefundPayment(ctx context.Context, refund domain.Refund) {
+	refund, err := s.Repo.RefundPayment(ctx, refund)
+	if err != nil {
+		slog.Error("Failed to refund payment", "orderID", refund.OrderID, "refundID", refund.ID, "err", err)
+		return
+	}
+	slog.Info("Payment refund recorded", "orderID", refund.OrderID, "refundID", refund.ID, "amount", refund.Amount, "full", refund.Full, "status", refund.Status)
+
+	if refund.Status == domain.StatusSucceeded {
+		err = s.Kafka.PublishRefundSucceededEvent(refund)
+	} else {
+		err = s.Kafka.PublishRefundFailedEvent(refund)
+	}
+	if err != nil {
+		slog.Error("Failed to publish refund event", "orderID", refund.OrderID, "refundID", refund.ID, "err", err)
+	}
+}
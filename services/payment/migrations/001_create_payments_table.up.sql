@@ -0,0 +1,7 @@
+-- One row per order, status can only be: 'succeeded', 'failed', 'refunded', 'voided'
+CREATE TABLE payments (
+    order_id VARCHAR(36) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'failed', 'refunded', 'voided')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,13 @@
+-- One row per refund asked for by the order service; status is the outcome, 'succeeded' or 'failed'
+CREATE TABLE IF NOT EXISTS refunds (
+    refund_id VARCHAR(36) PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    full_refund BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_order_id ON refunds (order_id);
@@ -0,0 +1,2 @@
+ALTER TABLE refunds ALTER COLUMN amount_minor TYPE NUMERIC(12,2) USING amount_minor / 100.0;
+ALTER TABLE refunds RENAME COLUMN amount_minor TO amount;
@@ -0,0 +1,3 @@
+-- Amounts are kept as whole minor units of the currency, cents for USD.
+ALTER TABLE refunds RENAME COLUMN amount TO amount_minor;
+ALTER TABLE refunds ALTER COLUMN amount_minor TYPE BIGINT USING ROUND(amount_minor * 100);
@@ -0,0 +1,8 @@
+-- The total of each order as priced by the order service, taken from its
+-- OrderCreated event; charges and refunds are checked against it
+CREATE TABLE IF NOT EXISTS order_totals (
+    order_id VARCHAR(36) PRIMARY KEY,
+    total_minor BIGINT NOT NULL CHECK (total_minor >= 0),
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
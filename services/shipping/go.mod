@@ -0,0 +1,15 @@
+module github.com/axmz/go-saga-microservices/shipping-service
+
+go 1.24.4
+
+require (
+	github.com/axmz/go-graceful v0.1.1
+	github.com/google/uuid v1.6.0
+	github.com/segmentio/kafka-go v0.4.48
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+)
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/http"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/carrier"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/consumer"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/handler"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/repository"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/router"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/service"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/tracker"
+)
+
+type App struct {
+	Cleaner  *db.OutboxCleaner
+	Config   *config.Config
+	Consumer *consumer.Consumer
+	DB       *db.DB
+	HTTP     *http.Server
+	Kafka    *kafka.Broker
+	Log      *slog.Logger
+	Relay    *db.OutboxRelay
+	Repo     *repository.Repository
+	Services *service.Service
+	Tracker  *tracker.Tracker
+}
+
+func SetupApp(
+	cfg *config.Config,
+	log *slog.Logger,
+	database *db.DB,
+	srv *http.Server,
+	kfk *kafka.Broker,
+	car carrier.Carrier,
+) (*App, error) {
+	rep := repository.New(database)
+	svc := service.New(rep, car)
+	cln := db.NewOutboxCleaner(database, cfg.Shipping.Outbox.Mode == config.OutboxModeRelay, db.OutboxRetention(cfg.Shipping.Outbox.Retention))
+	han := handler.New(svc)
+	con := consumer.New(kfk, han)
+	trk := tracker.New(database, svc, cfg.Shipping.Tracking)
+	mux := router.New(han)
+	srv.Router.Handler = http.LoggingMiddleware(mux)
+
+	var rel *db.OutboxRelay
+	switch outbox := cfg.Shipping.Outbox; outbox.Mode {
+	case config.OutboxModeRelay:
+		rel = db.NewOutboxRelay(database, cfg.Shipping.Kafka.Addr, cfg.Shipping.Kafka.ProducerTopic, outbox.Interval, outbox.BatchSize, outbox.SlotToDrop())
+	case config.OutboxModeDebezium, "":
+		// Kafka Connect publishes the outbox
+	default:
+		return nil, fmt.Errorf("unknown outbox mode %q", outbox.Mode)
+	}
+
+	app := &App{
+		Cleaner:  cln,
+		Config:   cfg,
+		Consumer: con,
+		DB:       database,
+		HTTP:     srv,
+		Kafka:    kfk,
+		Log:      log,
+		Relay:    rel,
+		Repo:     rep,
+		Services: svc,
+		Tracker:  trk,
+	}
+
+	slog.Info("Application initialized", slog.String("env", app.Config.Env), slog.String("carrier", car.Name()))
+	return app, nil
+}
@@ -0,0 +1,42 @@
+package carrier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/domain"
+)
+
+// Label is what a carrier hands out for a booked shipment.
+type Label struct {
+	TrackingNumber string
+	TrackingURL    string
+}
+
+// Tracking is where a carrier says a shipment is. DispatchedAt and
+// DeliveredAt are set once the shipment got that far.
+type Tracking struct {
+	Status       domain.Status
+	DispatchedAt time.Time
+	DeliveredAt  time.Time
+}
+
+// Carrier books shipments and tracks them until they are delivered.
+type Carrier interface {
+	Name() string
+	// CreateShipment books the shipment. Booking the same shipment again
+	// returns the same label.
+	CreateShipment(ctx context.Context, s domain.Shipment) (Label, error)
+	Track(ctx context.Context, s domain.Shipment) (Tracking, error)
+}
+
+func New(cfg config.CarrierConfig) (Carrier, error) {
+	switch cfg.Name {
+	case config.CarrierFake, "":
+		return NewFake(cfg.DispatchAfter, cfg.DeliverAfter), nil
+	default:
+		return nil, fmt.Errorf("unknown carrier %q", cfg.Name)
+	}
+}
@@ -0,0 +1,57 @@
+package carrier
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/domain"
+)
+
+const fakeTrackingURL = "https://tracking.example.com/fake/"
+
+// Fake is a carrier that makes up tracking numbers and dispatches and
+// delivers every shipment a fixed time after it was booked.
+type Fake struct {
+	DispatchAfter time.Duration
+	DeliverAfter  time.Duration
+}
+
+func NewFake(dispatchAfter, deliverAfter time.Duration) *Fake {
+	if deliverAfter < dispatchAfter {
+		deliverAfter = dispatchAfter
+	}
+	return &Fake{DispatchAfter: dispatchAfter, DeliverAfter: deliverAfter}
+}
+
+func (f *Fake) Name() string {
+	return config.CarrierFake
+}
+
+// CreateShipment derives the tracking number from the shipment id, so that
+// booking again hands out the same one.
+func (f *Fake) CreateShipment(_ context.Context, s domain.Shipment) (Label, error) {
+	number := "FX" + strings.ToUpper(strings.ReplaceAll(s.ID, "-", ""))[:12]
+	return Label{
+		TrackingNumber: number,
+		TrackingURL:    fakeTrackingURL + number,
+	}, nil
+}
+
+func (f *Fake) Track(_ context.Context, s domain.Shipment) (Tracking, error) {
+	if s.BookedAt == nil {
+		return Tracking{Status: s.Status}, nil
+	}
+
+	t := Tracking{Status: domain.StatusCreated}
+	if dispatched := s.BookedAt.Add(f.DispatchAfter); !time.Now().Before(dispatched) {
+		t.Status = domain.StatusDispatched
+		t.DispatchedAt = dispatched
+	}
+	if delivered := s.BookedAt.Add(f.DeliverAfter); !time.Now().Before(delivered) {
+		t.Status = domain.StatusDelivered
+		t.DeliveredAt = delivered
+	}
+	return t, nil
+}
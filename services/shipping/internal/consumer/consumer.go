@@ -0,0 +1,37 @@
+package consumer
+
+import (
+	"context"
+	"log/slog"
+
+	kafkaadapter "github.com/axmz/go-saga-microservices/lib/adapter/kafka"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/handler"
+	"github.com/segmentio/kafka-go"
+)
+
+type Consumer struct {
+	Broker  *kafkaadapter.Broker
+	Handler *handler.Handler
+}
+
+func New(b *kafkaadapter.Broker, h *handler.Handler) *Consumer {
+	return &Consumer{Broker: b, Handler: h}
+}
+
+func (c *Consumer) Start(ctx context.Context) error {
+	slog.Info("Consumer started")
+	return c.Broker.ListenAndHandle(ctx, c.handle)
+}
+
+func (c *Consumer) handle(ctx context.Context, m kafka.Message) error {
+	slog.Info("Kafka message", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
+	switch m.Topic {
+	case "order.events":
+		return c.Handler.OrderEvents(ctx, m)
+	case "payment.events":
+		return c.Handler.PaymentEvents(ctx, m)
+	default:
+		slog.Warn("Unhandled event", "topic", m.Topic)
+		return nil
+	}
+}
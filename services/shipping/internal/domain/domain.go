@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusAwaitingOrder is a payment that arrived before its order; the
+	// shipment is booked once the order is registered.
+	StatusAwaitingOrder Status = "awaiting_order"
+	// StatusAwaitingPayment is an order known to shipping that was not paid yet.
+	StatusAwaitingPayment Status = "awaiting_payment"
+	// StatusBooking is a paid order the carrier is being asked to book. It is
+	// left there when the carrier fails, until the booking is retried.
+	StatusBooking Status = "booking"
+	// StatusCreated is a shipment booked with the carrier, waiting to be picked up.
+	StatusCreated    Status = "created"
+	StatusDispatched Status = "dispatched"
+	StatusDelivered  Status = "delivered"
+	// StatusCancelled marks an order cancelled, expired or refunded in full
+	// before the carrier picked it up; it is never shipped.
+	StatusCancelled Status = "cancelled"
+)
+
+var ErrShipmentNotFound = errors.New("shipment not found")
+
+type Address struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+type Item struct {
+	ID       string `json:"id"`
+	Quantity int32  `json:"quantity"`
+}
+
+// Shipment is the delivery of one order. It is registered when the order is
+// created and booked with the carrier once the order is paid.
+type Shipment struct {
+	ID             string     `json:"id"`
+	OrderID        string     `json:"order_id"`
+	Status         Status     `json:"status"`
+	Email          string     `json:"email"`
+	Address        Address    `json:"address"`
+	Items          []Item     `json:"items"`
+	Carrier        string     `json:"carrier,omitempty"`
+	TrackingNumber string     `json:"tracking_number,omitempty"`
+	TrackingURL    string     `json:"tracking_url,omitempty"`
+	BookedAt       *time.Time `json:"booked_at,omitempty"`
+	DispatchedAt   *time.Time `json:"dispatched_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/domain"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/service"
+	"github.com/segmentio/kafka-go"
+)
+
+type Handler struct {
+	Service *service.Service
+}
+
+func New(service *service.Service) *Handler {
+	return &Handler{
+		Service: service,
+	}
+}
+
+func (h *Handler) GetShipment(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue("orderID")
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
+		return
+	}
+
+	shipment, err := h.Service.GetShipment(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrShipmentNotFound) {
+			httputils.ErrorNotFound(w, err)
+			return
+		}
+		slog.Error("GetShipment service error", "orderId", orderID, "err", err)
+		httputils.ErrorInternal(w, nil)
+		return
+	}
+
+	resp := &httppb.GetShipmentResponse{
+		OrderId:  shipment.OrderID,
+		Shipment: toProtoShipment(shipment),
+	}
+	httputils.RespondProto(w, resp, http.StatusOK)
+}
+
+func (h *Handler) OrderEvents(ctx context.Context, event kafka.Message) error {
+	envelope, err := codec.Decode[*events.OrderEventEnvelope](event)
+	if err != nil {
+		return err
+	}
+	switch evt := envelope.Event.(type) {
+	case *events.OrderEventEnvelope_OrderCreated:
+		created := evt.OrderCreated
+		slog.Info("Order event: created", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", created.Id)
+		return h.Service.RegisterOrder(ctx, created.Id, created.Email, toDomainAddress(created.ShippingAddress), toDomainItems(created.Items))
+	case *events.OrderEventEnvelope_OrderCancelled:
+		slog.Info("Order event: cancelled", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderCancelled.Id)
+		return h.Service.CancelShipment(ctx, evt.OrderCancelled.Id)
+	case *events.OrderEventEnvelope_OrderExpired:
+		slog.Info("Order event: expired", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderExpired.Id)
+		return h.Service.CancelShipment(ctx, evt.OrderExpired.Id)
+	case *events.OrderEventEnvelope_OrderRefunded:
+		slog.Info("Order event: refunded", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.OrderRefunded.Id, "full", evt.OrderRefunded.Full)
+		if evt.OrderRefunded.Full {
+			return h.Service.CancelShipment(ctx, evt.OrderRefunded.Id)
+		}
+	default:
+		// other order events need nothing from shipping
+	}
+	return nil
+}
+
+func (h *Handler) PaymentEvents(ctx context.Context, event kafka.Message) error {
+	envelope, err := codec.Decode[*events.PaymentEventEnvelope](event)
+	if err != nil {
+		return err
+	}
+	switch evt := envelope.Event.(type) {
+	case *events.PaymentEventEnvelope_PaymentSucceeded:
+		slog.Info("Payment event: succeeded", "topic", event.Topic, "partition", event.Partition, "offset", event.Offset, "orderId", evt.PaymentSucceeded.Id)
+		return h.Service.ShipOrder(ctx, evt.PaymentSucceeded.Id)
+	default:
+		// only paid orders are shipped
+	}
+	return nil
+}
+
+func toDomainAddress(a *events.Address) domain.Address {
+	if a == nil {
+		return domain.Address{}
+	}
+	return domain.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+func toDomainItems(items []*events.Item) []domain.Item {
+	out := make([]domain.Item, 0, len(items))
+	for _, it := range items {
+		out = append(out, domain.Item{ID: it.Id, Quantity: it.Quantity})
+	}
+	return out
+}
+
+// protoStatuses names the shipment statuses the way the order API does.
+var protoStatuses = map[domain.Status]string{
+	domain.StatusAwaitingPayment: "AwaitingPayment",
+	domain.StatusCreated:         "Created",
+	domain.StatusDispatched:      "Dispatched",
+	domain.StatusDelivered:       "Delivered",
+	domain.StatusCancelled:       "Cancelled",
+}
+
+func toProtoShipment(s domain.Shipment) *httppb.Shipment {
+	return &httppb.Shipment{
+		Id:             s.ID,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		TrackingUrl:    s.TrackingURL,
+		Status:         protoStatuses[s.Status],
+		UpdatedAt:      s.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
+	"github.com/axmz/go-saga-microservices/pkg/proto/events"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/domain"
+	"github.com/google/uuid"
+)
+
+// newShippingOutboxMessage wraps env in an outbox message keyed by the order
+// id. The message id doubles as the event id, so consumers see the same id
+// however often it is delivered.
+func newShippingOutboxMessage(orderID string, env *events.ShippingEventEnvelope) (db.OutboxMessage, error) {
+	msg := db.OutboxMessage{
+		ID:            uuid.New(),
+		AggregateType: "shipping",
+		AggregateID:   orderID,
+		EventType:     codec.EventType(env),
+	}
+	env.EventId = msg.ID.String()
+	payload, err := codec.Marshal(env)
+	if err != nil {
+		return msg, err
+	}
+	msg.Payload = payload
+	return msg, nil
+}
+
+// insertShipmentEventsTx writes an event to the outbox for every step s took
+// from status from, so that a shipment delivered between two looks of the
+// tracker is still announced as dispatched first.
+func (r *Repository) insertShipmentEventsTx(ctx context.Context, tx *sql.Tx, from domain.Status, s domain.Shipment) error {
+	for _, env := range shipmentEvents(from, s) {
+		msg, err := newShippingOutboxMessage(s.OrderID, env)
+		if err != nil {
+			return err
+		}
+		if err := r.DB.InsertOutbox(ctx, tx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func shipmentEvents(from domain.Status, s domain.Shipment) []*events.ShippingEventEnvelope {
+	var envs []*events.ShippingEventEnvelope
+	if from == domain.StatusBooking && s.BookedAt != nil {
+		envs = append(envs, &events.ShippingEventEnvelope{
+			Event: &events.ShippingEventEnvelope_ShipmentCreated{
+				ShipmentCreated: &events.ShipmentCreated{
+					Id:             s.OrderID,
+					ShipmentId:     s.ID,
+					Carrier:        s.Carrier,
+					TrackingNumber: s.TrackingNumber,
+					TrackingUrl:    s.TrackingURL,
+					CreatedAt:      formatTime(s.BookedAt),
+				},
+			},
+		})
+	}
+	if from != domain.StatusDispatched && from != domain.StatusDelivered && s.DispatchedAt != nil {
+		envs = append(envs, &events.ShippingEventEnvelope{
+			Event: &events.ShippingEventEnvelope_ShipmentDispatched{
+				ShipmentDispatched: &events.ShipmentDispatched{
+					Id:             s.OrderID,
+					ShipmentId:     s.ID,
+					Carrier:        s.Carrier,
+					TrackingNumber: s.TrackingNumber,
+					DispatchedAt:   formatTime(s.DispatchedAt),
+				},
+			},
+		})
+	}
+	if from != domain.StatusDelivered && s.DeliveredAt != nil {
+		envs = append(envs, &events.ShippingEventEnvelope{
+			Event: &events.ShippingEventEnvelope_ShipmentDelivered{
+				ShipmentDelivered: &events.ShipmentDelivered{
+					Id:             s.OrderID,
+					ShipmentId:     s.ID,
+					Carrier:        s.Carrier,
+					TrackingNumber: s.TrackingNumber,
+					DeliveredAt:    formatTime(s.DeliveredAt),
+				},
+			},
+		})
+	}
+	return envs
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
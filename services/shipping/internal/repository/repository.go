@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/domain"
+)
+
+type Repository struct {
+	DB *db.DB
+}
+
+func New(db *db.DB) *Repository {
+	return &Repository{DB: db}
+}
+
+const shipmentColumns = `id, order_id, status, email, address, items, carrier, tracking_number, tracking_url,
+	booked_at, dispatched_at, delivered_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanShipment(row rowScanner) (domain.Shipment, error) {
+	var (
+		s                  domain.Shipment
+		address, items     []byte
+		booked, dispatched sql.NullTime
+		delivered          sql.NullTime
+	)
+	err := row.Scan(&s.ID, &s.OrderID, &s.Status, &s.Email, &address, &items, &s.Carrier, &s.TrackingNumber, &s.TrackingURL,
+		&booked, &dispatched, &delivered, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(address, &s.Address); err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(items, &s.Items); err != nil {
+		return s, err
+	}
+	if booked.Valid {
+		s.BookedAt = &booked.Time
+	}
+	if dispatched.Valid {
+		s.DispatchedAt = &dispatched.Time
+	}
+	if delivered.Valid {
+		s.DeliveredAt = &delivered.Time
+	}
+	return s, nil
+}
+
+// RegisterShipment stores the shipment of a newly created order, awaiting its
+// payment. The shipment of an order whose payment was parked, or whose booking
+// did not get through, is filled in and returned in StatusBooking to be
+// booked. It returns false when the order was registered before.
+func (r *Repository) RegisterShipment(ctx context.Context, s domain.Shipment) (domain.Shipment, bool, error) {
+	address, err := json.Marshal(s.Address)
+	if err != nil {
+		return s, false, err
+	}
+	items, err := json.Marshal(s.Items)
+	if err != nil {
+		return s, false, err
+	}
+
+	registered, err := scanShipment(r.DB.GetConn().QueryRowContext(ctx, `
+		INSERT INTO shipments (id, order_id, status, email, address, items)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (order_id) DO UPDATE
+		SET status = $7, email = EXCLUDED.email, address = EXCLUDED.address, items = EXCLUDED.items, updated_at = now()
+		WHERE shipments.status IN ($8, $7)
+		RETURNING `+shipmentColumns,
+		s.ID, s.OrderID, domain.StatusAwaitingPayment, s.Email, address, items, domain.StatusBooking, domain.StatusAwaitingOrder))
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, false, nil
+	}
+	if err != nil {
+		return s, false, err
+	}
+	return registered, true, nil
+}
+
+// ReserveBooking takes the shipment of a paid order into StatusBooking and
+// returns it with true, unless it is past awaiting its payment. The payment of
+// an order that was not registered yet is parked in a new shipment with id id
+// in StatusAwaitingOrder.
+func (r *Repository) ReserveBooking(ctx context.Context, orderID, id string) (domain.Shipment, bool, error) {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return domain.Shipment{}, false, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO shipments (id, order_id, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (order_id) DO NOTHING
+	`, id, orderID, domain.StatusAwaitingOrder)
+	if err != nil {
+		_ = tx.Rollback()
+		return domain.Shipment{}, false, err
+	}
+	if rows, _ := res.RowsAffected(); rows == 1 {
+		return domain.Shipment{ID: id, OrderID: orderID, Status: domain.StatusAwaitingOrder}, false, tx.Commit()
+	}
+
+	s, err := scanShipment(tx.QueryRowContext(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE order_id = $1 FOR UPDATE`, orderID))
+	if err != nil {
+		_ = tx.Rollback()
+		return s, false, err
+	}
+	switch s.Status {
+	case domain.StatusAwaitingPayment:
+		s.Status = domain.StatusBooking
+		if _, err := tx.ExecContext(ctx, `UPDATE shipments SET status = $1, updated_at = now() WHERE id = $2`, s.Status, s.ID); err != nil {
+			_ = tx.Rollback()
+			return s, false, err
+		}
+	case domain.StatusBooking:
+		// an earlier booking did not get through, the carrier is asked again
+	default:
+		return s, false, tx.Commit()
+	}
+	return s, true, tx.Commit()
+}
+
+// CancelShipment gives up the shipment of an order that was cancelled,
+// expired or refunded in full. A shipment being booked or booked for a payment
+// that raced the cancel is given up as well, as long as the carrier did not
+// pick it up. It returns false when there was nothing to cancel.
+func (r *Repository) CancelShipment(ctx context.Context, orderID string) (bool, error) {
+	res, err := r.DB.GetConn().ExecContext(ctx, `
+		UPDATE shipments SET status = $1, updated_at = now()
+		WHERE order_id = $2 AND status IN ($3, $4, $5)
+	`, domain.StatusCancelled, orderID, domain.StatusAwaitingPayment, domain.StatusBooking, domain.StatusCreated)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := res.RowsAffected()
+	return rows == 1, nil
+}
+
+func (r *Repository) GetShipment(ctx context.Context, orderID string) (domain.Shipment, error) {
+	row := r.DB.GetConn().QueryRowContext(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE order_id = $1`, orderID)
+	s, err := scanShipment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, domain.ErrShipmentNotFound
+	}
+	return s, err
+}
+
+// UpdateShipment locks the shipment of an order and lets fn change it. The
+// changes are stored when fn returns true, together with an event in the
+// outbox for every step the shipment took; fn returning an error rolls back.
+func (r *Repository) UpdateShipment(ctx context.Context, orderID string, fn func(*domain.Shipment) (bool, error)) error {
+	tx, err := r.DB.GetConn().BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	s, err := scanShipment(tx.QueryRowContext(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE order_id = $1 FOR UPDATE`, orderID))
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrShipmentNotFound
+		}
+		return err
+	}
+
+	from := s.Status
+	changed, err := fn(&s)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if !changed {
+		return tx.Commit()
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE shipments
+		SET status = $1, carrier = $2, tracking_number = $3, tracking_url = $4,
+			booked_at = $5, dispatched_at = $6, delivered_at = $7, updated_at = now()
+		WHERE id = $8
+	`, s.Status, s.Carrier, s.TrackingNumber, s.TrackingURL, s.BookedAt, s.DispatchedAt, s.DeliveredAt, s.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := r.insertShipmentEventsTx(ctx, tx, from, s); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListOpenShipments returns up to limit booked shipments that are not
+// delivered yet, those looked at least recently first.
+func (r *Repository) ListOpenShipments(ctx context.Context, limit int) ([]domain.Shipment, error) {
+	rows, err := r.DB.GetConn().QueryContext(ctx, `
+		SELECT `+shipmentColumns+`
+		FROM shipments
+		WHERE status IN ($1, $2)
+		ORDER BY updated_at
+		LIMIT $3
+	`, domain.StatusCreated, domain.StatusDispatched, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shipments []domain.Shipment
+	for rows.Next() {
+		s, err := scanShipment(rows)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, s)
+	}
+	return shipments, rows.Err()
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/handler"
+)
+
+func New(handlers *handler.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /shipments/{orderID}", handlers.GetShipment)
+	return mux
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/carrier"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/domain"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	Repo    *repository.Repository
+	Carrier carrier.Carrier
+}
+
+func New(repo *repository.Repository, carrier carrier.Carrier) *Service {
+	return &Service{
+		Repo:    repo,
+		Carrier: carrier,
+	}
+}
+
+// RegisterOrder remembers where a new order goes, so that it can be shipped
+// once it is paid. An order whose payment came first is booked right away.
+func (s *Service) RegisterOrder(ctx context.Context, orderID, email string, address domain.Address, items []domain.Item) error {
+	shipment := domain.Shipment{
+		ID:      uuid.New().String(),
+		OrderID: orderID,
+		Email:   email,
+		Address: address,
+		Items:   items,
+	}
+	registered, created, err := s.Repo.RegisterShipment(ctx, shipment)
+	if err != nil {
+		return fmt.Errorf("register order %s for shipping: %w", orderID, err)
+	}
+	if !created {
+		slog.Info("Order already registered for shipping", "orderID", orderID)
+		return nil
+	}
+	slog.Info("Order registered for shipping", "orderID", orderID, "shipmentID", registered.ID, "status", registered.Status)
+	if registered.Status == domain.StatusBooking {
+		return s.book(ctx, registered)
+	}
+	return nil
+}
+
+// CancelShipment gives up the shipment of an order that was cancelled, expired
+// or refunded in full. A shipment the carrier picked up already cannot be
+// called back and is only logged.
+func (s *Service) CancelShipment(ctx context.Context, orderID string) error {
+	cancelled, err := s.Repo.CancelShipment(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("cancel shipment of order %s: %w", orderID, err)
+	}
+	if cancelled {
+		slog.Info("Shipment cancelled", "orderID", orderID)
+		return nil
+	}
+	shipment, err := s.Repo.GetShipment(ctx, orderID)
+	if err == nil && (shipment.Status == domain.StatusDispatched || shipment.Status == domain.StatusDelivered) {
+		slog.Warn("Shipment already picked up by the carrier, not cancelled", "orderID", orderID, "status", shipment.Status)
+	}
+	return nil
+}
+
+// ShipOrder books the shipment of a paid order with the carrier and announces
+// it. A shipment that was booked or cancelled before is left alone. The payment
+// of an order shipping has not registered yet, its OrderCreated still on the
+// way, is parked, and RegisterOrder books the shipment.
+func (s *Service) ShipOrder(ctx context.Context, orderID string) error {
+	shipment, reserved, err := s.Repo.ReserveBooking(ctx, orderID, uuid.New().String())
+	if err != nil {
+		return fmt.Errorf("ship order %s: %w", orderID, err)
+	}
+	if !reserved {
+		if shipment.Status == domain.StatusAwaitingOrder {
+			slog.Info("Payment of an order not registered yet, parked", "orderID", orderID)
+		} else {
+			slog.Info("Shipment not awaiting payment, skipping", "orderID", orderID, "status", shipment.Status)
+		}
+		return nil
+	}
+	return s.book(ctx, shipment)
+}
+
+// book asks the carrier for the label of a shipment in StatusBooking and
+// stores it. The carrier is called with no transaction open; a shipment that
+// was cancelled in the meantime keeps its status. When the carrier fails the
+// shipment stays in StatusBooking and the error is returned for the event to
+// be retried.
+func (s *Service) book(ctx context.Context, shipment domain.Shipment) error {
+	label, err := s.Carrier.CreateShipment(ctx, shipment)
+	if err != nil {
+		return fmt.Errorf("book shipment of order %s: %w", shipment.OrderID, err)
+	}
+
+	err = s.Repo.UpdateShipment(ctx, shipment.OrderID, func(shipment *domain.Shipment) (bool, error) {
+		if shipment.Status != domain.StatusBooking {
+			slog.Warn("Shipment no longer being booked, label dropped", "orderID", shipment.OrderID, "status", shipment.Status, "trackingNumber", label.TrackingNumber)
+			return false, nil
+		}
+		now := time.Now()
+		shipment.Status = domain.StatusCreated
+		shipment.Carrier = s.Carrier.Name()
+		shipment.TrackingNumber = label.TrackingNumber
+		shipment.TrackingURL = label.TrackingURL
+		shipment.BookedAt = &now
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("record shipment of order %s: %w", shipment.OrderID, err)
+	}
+	slog.Info("Order shipped", "orderID", shipment.OrderID)
+	return nil
+}
+
+// TrackShipments asks the carrier about up to limit open shipments and
+// announces every step each of them made since it was asked last. It returns
+// how many shipments moved on.
+func (s *Service) TrackShipments(ctx context.Context, limit int) (int, error) {
+	shipments, err := s.Repo.ListOpenShipments(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var moved int
+	for _, open := range shipments {
+		tracking, err := s.Carrier.Track(ctx, open)
+		if err != nil {
+			slog.Error("Failed to track shipment", "orderID", open.OrderID, "shipmentID", open.ID, "err", err)
+			continue
+		}
+
+		var stepped bool
+		err = s.Repo.UpdateShipment(ctx, open.OrderID, func(shipment *domain.Shipment) (bool, error) {
+			stepped = track(shipment, tracking)
+			// store the shipment even when it did not move, so that the
+			// others get their turn first next time
+			return true, nil
+		})
+		if err != nil {
+			slog.Error("Failed to track shipment", "orderID", open.OrderID, "shipmentID", open.ID, "err", err)
+			continue
+		}
+		if stepped {
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// track moves the shipment on to where the carrier says it is; UpdateShipment
+// announces each step it took. The carrier is asked before the shipment is
+// locked, so a shipment that changed in the meantime is only moved forward.
+func track(shipment *domain.Shipment, tracking carrier.Tracking) bool {
+	if shipment.Status != domain.StatusCreated && shipment.Status != domain.StatusDispatched {
+		return false
+	}
+
+	var stepped bool
+	if shipment.Status == domain.StatusCreated && (tracking.Status == domain.StatusDispatched || tracking.Status == domain.StatusDelivered) {
+		shipment.Status = domain.StatusDispatched
+		shipment.DispatchedAt = &tracking.DispatchedAt
+		stepped = true
+	}
+	if shipment.Status == domain.StatusDispatched && tracking.Status == domain.StatusDelivered {
+		shipment.Status = domain.StatusDelivered
+		shipment.DeliveredAt = &tracking.DeliveredAt
+		stepped = true
+	}
+	return stepped
+}
+
+func (s *Service) GetShipment(ctx context.Context, orderID string) (domain.Shipment, error) {
+	return s.Repo.GetShipment(ctx, orderID)
+}
@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/lib/adapter/db"
+	"github.com/axmz/go-saga-microservices/shipping-service/internal/service"
+)
+
+// advisoryLockKey elects the single replica that tracks at a given time.
+const advisoryLockKey int64 = 0x7368697070696e67 // "shipping"
+
+// Tracker periodically asks the carrier about booked shipments and announces
+// when they are dispatched and delivered.
+type Tracker struct {
+	DB      *db.DB
+	Service *service.Service
+	Cfg     config.TrackingConfig
+}
+
+func New(db *db.DB, svc *service.Service, cfg config.TrackingConfig) *Tracker {
+	return &Tracker{DB: db, Service: svc, Cfg: cfg}
+}
+
+func (t *Tracker) Start(ctx context.Context) error {
+	if t.Cfg.Interval <= 0 || t.Cfg.BatchSize <= 0 {
+		slog.Info("Shipment tracker disabled")
+		return nil
+	}
+
+	slog.Info("Shipment tracker started", "interval", t.Cfg.Interval, "batchSize", t.Cfg.BatchSize)
+	ticker := time.NewTicker(t.Cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			t.track(ctx)
+		}
+	}
+}
+
+func (t *Tracker) track(ctx context.Context) {
+	var moved int
+	leader, err := t.DB.TryAdvisoryLock(ctx, advisoryLockKey, func(ctx context.Context) error {
+		var err error
+		moved, err = t.Service.TrackShipments(ctx, t.Cfg.BatchSize)
+		return err
+	})
+	if err != nil {
+		slog.Error("Shipment tracking failed:", "err", err)
+		return
+	}
+	if !leader {
+		slog.Debug("Shipment tracking skipped, another replica is tracking")
+		return
+	}
+	if moved > 0 {
+		slog.Info("Shipment tracking finished", "moved", moved)
+	}
+}
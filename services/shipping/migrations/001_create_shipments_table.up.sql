@@ -0,0 +1,23 @@
+-- One row per order, registered when the order is created and booked with the
+-- carrier once it is paid. status can only be: 'awaiting_payment', 'created',
+-- 'dispatched', 'delivered', 'cancelled'
+CREATE TABLE IF NOT EXISTS shipments (
+    id VARCHAR(36) PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('awaiting_payment', 'created', 'dispatched', 'delivered', 'cancelled')),
+    email TEXT NOT NULL DEFAULT '',
+    address JSONB NOT NULL DEFAULT '{}',
+    items JSONB NOT NULL DEFAULT '[]',
+    carrier VARCHAR(50) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(100) NOT NULL DEFAULT '',
+    tracking_url TEXT NOT NULL DEFAULT '',
+    booked_at TIMESTAMPTZ,
+    dispatched_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- the tracker only looks at shipments the carrier still has to move on
+CREATE INDEX IF NOT EXISTS idx_shipments_open ON shipments (updated_at)
+    WHERE status IN ('created', 'dispatched');
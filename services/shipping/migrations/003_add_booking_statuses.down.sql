@@ -0,0 +1,5 @@
+DELETE FROM shipments WHERE status = 'awaiting_order';
+UPDATE shipments SET status = 'awaiting_payment' WHERE status = 'booking';
+ALTER TABLE shipments DROP CONSTRAINT IF EXISTS shipments_status_check;
+ALTER TABLE shipments ADD CONSTRAINT shipments_status_check
+    CHECK (status IN ('awaiting_payment', 'created', 'dispatched', 'delivered', 'cancelled'));
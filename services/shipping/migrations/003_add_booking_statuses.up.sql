@@ -0,0 +1,5 @@
+-- 'awaiting_order' parks a payment that arrived before its order, 'booking'
+-- holds a shipment while the carrier is asked for a label
+ALTER TABLE shipments DROP CONSTRAINT IF EXISTS shipments_status_check;
+ALTER TABLE shipments ADD CONSTRAINT shipments_status_check
+    CHECK (status IN ('awaiting_order', 'awaiting_payment', 'booking', 'created', 'dispatched', 'delivered', 'cancelled'));
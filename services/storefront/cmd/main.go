@@ -55,14 +55,20 @@ func main() {
 		log.Fatalf("Failed to initialize HTTP server %v", err)
 	}
 
+	// initialize admin server, kept off the public listener
+	asrv, err := http.NewServer(http.Config(cfg.Storefront.Admin))
+	if err != nil {
+		log.Fatalf("Failed to initialize admin server %v", err)
+	}
+
 	// initialize WebSocket manager
-	wsManager := ws.NewWSManager()
+	wsManager := ws.NewWSManager(cfg.Storefront.HTTP.Host)
 	if wsManager == nil {
 		log.Fatalf("Failed to create WebSocket manager")
 	}
 
 	// setup app
-	app, err := app.SetupApp(cfg, logger, srv, renderer, wsManager, kafka)
+	app, err := app.SetupApp(cfg, logger, srv, asrv, renderer, wsManager, kafka)
 	if err != nil {
 		log.Fatalf("Failed to initialize app %v", err)
 	}
@@ -77,6 +83,16 @@ func main() {
 		}
 	}()
 
+	// Admin server
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.Admin.Run(); err != nil {
+			slog.Error("Admin server terminated:", "err", err)
+			cancel()
+		}
+	}()
+
 	// Kafka consumer
 	wg.Add(1)
 	go func() {
@@ -89,8 +105,9 @@ func main() {
 
 	// Wait for shutdown signal or context cancellation
 	<-graceful.Shutdown(ctx, app.Config.GracefulTimeout, map[string]graceful.Operation{
-		"kafka":       app.Kafka.Shutdown,
-		"http-server": app.HTTP.Shutdown,
+		"kafka":        app.Kafka.Shutdown,
+		"http-server":  app.HTTP.Shutdown,
+		"admin-server": app.Admin.Shutdown,
 	})
 
 	app.Log.Info("Application stopped")
@@ -1,9 +1,11 @@
 package app
 
 import (
+	"fmt"
 	"log/slog"
 
 	"github.com/axmz/go-saga-microservices/config"
+	"github.com/axmz/go-saga-microservices/lib/adapter/grpc"
 	"github.com/axmz/go-saga-microservices/lib/adapter/http"
 	"github.com/axmz/go-saga-microservices/lib/adapter/kafka"
 	"github.com/axmz/go-saga-microservices/services/storefront/internal/client"
@@ -16,6 +18,7 @@ import (
 )
 
 type App struct {
+	Admin     *http.Server
 	Config    *config.Config
 	HTTP      *http.Server
 	Log       *slog.Logger
@@ -29,20 +32,24 @@ func SetupApp(
 	cfg *config.Config,
 	log *slog.Logger,
 	srv *http.Server,
+	asrv *http.Server,
 	renderer *renderer.TemplateRenderer,
 	wsManager *ws.WSManager,
 	kfk *kafka.Broker,
 ) (*App, error) {
-	ocl := client.NewHTTPOrderClient(cfg.Order.HTTP.URL())
-	pcl := client.NewHTTPPaymentClient(cfg.Payment.HTTP.URL())
-	icl := client.NewHTTPInventoryClient(cfg.Inventory.HTTP.URL())
+	ocl, pcl, icl, err := newClients(cfg)
+	if err != nil {
+		return nil, err
+	}
 	svc := service.New(cfg, ocl, pcl, icl)
 	han := handler.New(svc, renderer, wsManager)
 	mux := router.New(han, svc, renderer)
-	con := consumer.New(kfk.Reader, han)
+	con := consumer.New(kfk, han)
 	srv.Router.Handler = http.LoggingMiddleware(mux)
+	asrv.Router.Handler = http.LoggingMiddleware(router.Admin(han))
 
 	app := &App{
+		Admin:    asrv,
 		Config:   cfg,
 		HTTP:     srv,
 		Log:      log,
@@ -53,3 +60,34 @@ func SetupApp(
 	slog.Info("Application initialized", slog.String("env", app.Config.Env))
 	return app, nil
 }
+
+// newClients creates the clients of the other services over the configured
+// transport.
+func newClients(cfg *config.Config) (client.OrderClient, client.PaymentClient, client.InventoryClient, error) {
+	switch cfg.Storefront.Transport {
+	case config.TransportHTTP, "":
+		return client.NewHTTPOrderClient(cfg.Order.HTTP.URL(), cfg.Storefront.AsyncCreateOrder),
+			client.NewHTTPPaymentClient(cfg.Payment.HTTP.URL()),
+			client.NewHTTPInventoryClient(cfg.Inventory.HTTP.URL(), cfg.Storefront.InventoryToken),
+			nil
+	case config.TransportGRPC:
+		oconn, err := grpc.Dial(cfg.Order.GRPC.Addr())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("dial order service: %w", err)
+		}
+		pconn, err := grpc.Dial(cfg.Payment.GRPC.Addr())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("dial payment service: %w", err)
+		}
+		iconn, err := grpc.Dial(cfg.Inventory.GRPC.Addr())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("dial inventory service: %w", err)
+		}
+		return client.NewGRPCOrderClient(oconn, cfg.Storefront.AsyncCreateOrder),
+			client.NewGRPCPaymentClient(pconn),
+			client.NewGRPCInventoryClient(iconn, cfg.Storefront.InventoryToken),
+			nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown transport %q", cfg.Storefront.Transport)
+	}
+}
@@ -2,43 +2,92 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
+// ErrInvalidProductQuery means the inventory service rejected a product
+// listing request, for example for a bad cursor, sort or status.
+var ErrInvalidProductQuery = errors.New("invalid product query")
+
 type InventoryClient interface {
-	GetProducts(ctx context.Context) (*httppb.GetProductsResponse, error)
+	// GetProducts lists a page of products. When req.IfNoneMatch still names
+	// the catalog version, the response only has NotModified and Etag set.
+	GetProducts(ctx context.Context, req *httppb.GetProductsRequest) (*httppb.GetProductsResponse, error)
 	ResetAll(ctx context.Context) error
 }
 
 type HTTPInventoryClient struct {
 	baseURL string
 	client  *http.Client
+	// adminToken is the bearer token ResetAll signs in with.
+	adminToken string
 }
 
-func NewHTTPInventoryClient(baseURL string) *HTTPInventoryClient {
+func NewHTTPInventoryClient(baseURL, adminToken string) *HTTPInventoryClient {
 	return &HTTPInventoryClient{
-		baseURL: baseURL,
-		client:  &http.Client{},
+		baseURL:    baseURL,
+		client:     &http.Client{},
+		adminToken: adminToken,
 	}
 }
 
-func (c *HTTPInventoryClient) GetProducts(ctx context.Context) (*httppb.GetProductsResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/products", nil)
+func (c *HTTPInventoryClient) GetProducts(ctx context.Context, req *httppb.GetProductsRequest) (*httppb.GetProductsResponse, error) {
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"status": req.GetStatus(),
+		"q":      req.GetQ(),
+		"sort":   req.GetSort(),
+		"cursor": req.GetCursor(),
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	if req.GetMinPrice() > 0 {
+		q.Set("min_price", strconv.FormatFloat(req.GetMinPrice(), 'f', -1, 64))
+	}
+	if req.GetMaxPrice() > 0 {
+		q.Set("max_price", strconv.FormatFloat(req.GetMaxPrice(), 'f', -1, 64))
+	}
+	if req.GetLimit() > 0 {
+		q.Set("limit", strconv.Itoa(int(req.GetLimit())))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/products?"+q.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
+	if req.GetIfNoneMatch() != "" {
+		httpReq.Header.Set("If-None-Match", req.GetIfNoneMatch())
+	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.client.Do(httpReq)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return &httppb.GetProductsResponse{Etag: resp.Header.Get("ETag"), NotModified: true}, nil
+	case http.StatusBadRequest:
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProductQuery, strings.TrimSpace(string(msg)))
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("inventory service returned status: %d", resp.StatusCode)
 	}
@@ -61,6 +110,7 @@ func (c *HTTPInventoryClient) ResetAll(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Authorization", "Bearer "+c.adminToken)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -73,3 +123,30 @@ func (c *HTTPInventoryClient) ResetAll(ctx context.Context) error {
 	}
 	return nil
 }
+
+// GRPCInventoryClient talks to the InventoryService gRPC API.
+type GRPCInventoryClient struct {
+	client     rpc.InventoryServiceClient
+	adminToken string
+}
+
+func NewGRPCInventoryClient(conn *grpc.ClientConn, adminToken string) *GRPCInventoryClient {
+	return &GRPCInventoryClient{
+		client:     rpc.NewInventoryServiceClient(conn),
+		adminToken: adminToken,
+	}
+}
+
+func (c *GRPCInventoryClient) GetProducts(ctx context.Context, req *httppb.GetProductsRequest) (*httppb.GetProductsResponse, error) {
+	resp, err := c.client.GetProducts(ctx, req)
+	if status.Code(err) == codes.InvalidArgument {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProductQuery, status.Convert(err).Message())
+	}
+	return resp, err
+}
+
+func (c *GRPCInventoryClient) ResetAll(ctx context.Context) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.adminToken)
+	_, err := c.client.ResetProducts(ctx, &httppb.ResetProductsRequest{})
+	return err
+}
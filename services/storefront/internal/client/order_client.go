@@ -3,44 +3,97 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 type OrderClient interface {
-	CreateOrder(ctx context.Context, req *httppb.CreateOrderRequest) (*httppb.CreateOrderResponse, error)
+	CreateOrder(ctx context.Context, req *httppb.CreateOrderRequest, idempotencyKey string) (*httppb.CreateOrderResponse, error)
 	GetOrder(ctx context.Context, orderID string) (*httppb.GetOrderResponse, error)
+	GetOrderHistory(ctx context.Context, orderID string) (*httppb.GetOrderHistoryResponse, error)
+	ListOrders(ctx context.Context, req *httppb.ListOrdersRequest) (*httppb.ListOrdersResponse, error)
+	CancelOrder(ctx context.Context, orderID, customerID string) (*httppb.CancelOrderResponse, error)
+	RefundOrder(ctx context.Context, orderID string, req *httppb.RefundOrderRequest) (*httppb.RefundOrderResponse, error)
 }
 
+var (
+	// ErrIdempotencyKeyReused means the key was already used for a different order request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+	// ErrInvalidOrder means the order service rejected the order request as malformed.
+	ErrInvalidOrder = errors.New("invalid order")
+	// ErrInvalidRefund means the refund asks for items the order does not have left.
+	ErrInvalidRefund = errors.New("invalid refund")
+	// ErrRefundRejected means the order cannot be refunded in its current status.
+	ErrRefundRejected = errors.New("refund rejected")
+	// ErrOrderNotFound means there is no such order, or it is another customer's.
+	ErrOrderNotFound = errors.New("order not found")
+)
+
 type HTTPOrderClient struct {
 	baseURL string
 	client  *http.Client
+	async   bool
 }
 
-func NewHTTPOrderClient(baseURL string) *HTTPOrderClient {
+// NewHTTPOrderClient creates an order client. With async set, CreateOrder asks the
+// order service not to wait for the saga and returns the order while still Pending.
+func NewHTTPOrderClient(baseURL string, async bool) *HTTPOrderClient {
 	return &HTTPOrderClient{
 		baseURL: baseURL,
 		client:  &http.Client{},
+		async:   async,
 	}
 }
 
-func (c *HTTPOrderClient) CreateOrder(ctx context.Context, req *httppb.CreateOrderRequest) (*httppb.CreateOrderResponse, error) {
+// CreateOrder places an order. A non-empty idempotencyKey is sent as the
+// Idempotency-Key header so that retries return the original order.
+func (c *HTTPOrderClient) CreateOrder(ctx context.Context, req *httppb.CreateOrderRequest, idempotencyKey string) (*httppb.CreateOrderResponse, error) {
 	protoData, err := proto.Marshal(req)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.client.Post(c.baseURL+"/orders", "application/x-protobuf", bytes.NewBuffer(protoData))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/orders", bytes.NewBuffer(protoData))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	if c.async {
+		httpReq.Header.Set("Prefer", "respond-async")
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.client.Do(httpReq)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOrder, strings.TrimSpace(string(msg)))
+	case http.StatusUnprocessableEntity:
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	// 202 Accepted means the order is still Pending, the body has the same shape
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
 		return nil, fmt.Errorf("order service returned status: %d", resp.StatusCode)
 	}
 
@@ -80,3 +133,245 @@ func (c *HTTPOrderClient) GetOrder(ctx context.Context, orderID string) (*httppb
 
 	return &protoResp, nil
 }
+
+func (c *HTTPOrderClient) GetOrderHistory(ctx context.Context, orderID string) (*httppb.GetOrderHistoryResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/orders/"+orderID+"/history", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("order service returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var protoResp httppb.GetOrderHistoryResponse
+	if err := proto.Unmarshal(body, &protoResp); err != nil {
+		return nil, err
+	}
+
+	return &protoResp, nil
+}
+
+func (c *HTTPOrderClient) ListOrders(ctx context.Context, req *httppb.ListOrdersRequest) (*httppb.ListOrdersResponse, error) {
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"status":       req.GetStatus(),
+		"created_from": req.GetCreatedFrom(),
+		"created_to":   req.GetCreatedTo(),
+		"sku":          req.GetSku(),
+		"customer_id":  req.GetCustomerId(),
+		"cursor":       req.GetCursor(),
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	if req.GetLimit() > 0 {
+		q.Set("limit", strconv.Itoa(int(req.GetLimit())))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/orders?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("order service returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var protoResp httppb.ListOrdersResponse
+	if err := proto.Unmarshal(body, &protoResp); err != nil {
+		return nil, err
+	}
+
+	return &protoResp, nil
+}
+
+func (c *HTTPOrderClient) CancelOrder(ctx context.Context, orderID, customerID string) (*httppb.CancelOrderResponse, error) {
+	protoData, err := proto.Marshal(&httppb.CancelOrderRequest{CustomerId: customerID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/orders/"+orderID+"/cancel", bytes.NewBuffer(protoData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrOrderNotFound
+	}
+
+	// 409 Conflict means the order is past cancelling, the body carries the reason
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		return nil, fmt.Errorf("order service returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var protoResp httppb.CancelOrderResponse
+	if err := proto.Unmarshal(body, &protoResp); err != nil {
+		return nil, err
+	}
+
+	return &protoResp, nil
+}
+
+func (c *HTTPOrderClient) RefundOrder(ctx context.Context, orderID string, req *httppb.RefundOrderRequest) (*httppb.RefundOrderResponse, error) {
+	protoData, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/orders/"+orderID+"/refund", bytes.NewBuffer(protoData))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, ErrOrderNotFound
+	case http.StatusBadRequest:
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRefund, strings.TrimSpace(string(msg)))
+	case http.StatusConflict:
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s", ErrRefundRejected, strings.TrimSpace(string(msg)))
+	}
+
+	// 202 Accepted, payment has not answered yet
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("order service returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var protoResp httppb.RefundOrderResponse
+	if err := proto.Unmarshal(body, &protoResp); err != nil {
+		return nil, err
+	}
+
+	return &protoResp, nil
+}
+
+// GRPCOrderClient talks to the OrderService gRPC API and reports errors the
+// way HTTPOrderClient does.
+type GRPCOrderClient struct {
+	client rpc.OrderServiceClient
+	async  bool
+}
+
+// NewGRPCOrderClient creates an order client over conn. With async set,
+// CreateOrder asks the order service not to wait for the saga.
+func NewGRPCOrderClient(conn *grpc.ClientConn, async bool) *GRPCOrderClient {
+	return &GRPCOrderClient{
+		client: rpc.NewOrderServiceClient(conn),
+		async:  async,
+	}
+}
+
+// CreateOrder places an order. A non-empty idempotencyKey is sent as the
+// "idempotency-key" metadata so that retries return the original order.
+func (c *GRPCOrderClient) CreateOrder(ctx context.Context, req *httppb.CreateOrderRequest, idempotencyKey string) (*httppb.CreateOrderResponse, error) {
+	if c.async {
+		ctx = metadata.AppendToOutgoingContext(ctx, "prefer", "respond-async")
+	}
+	if idempotencyKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "idempotency-key", idempotencyKey)
+	}
+
+	resp, err := c.client.CreateOrder(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		return resp, nil
+	case codes.InvalidArgument:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOrder, status.Convert(err).Message())
+	case codes.FailedPrecondition:
+		return nil, ErrIdempotencyKeyReused
+	default:
+		return nil, err
+	}
+}
+
+func (c *GRPCOrderClient) GetOrder(ctx context.Context, orderID string) (*httppb.GetOrderResponse, error) {
+	return c.client.GetOrder(ctx, &httppb.GetOrderRequest{OrderId: orderID})
+}
+
+func (c *GRPCOrderClient) GetOrderHistory(ctx context.Context, orderID string) (*httppb.GetOrderHistoryResponse, error) {
+	return c.client.GetOrderHistory(ctx, &httppb.GetOrderHistoryRequest{OrderId: orderID})
+}
+
+func (c *GRPCOrderClient) ListOrders(ctx context.Context, req *httppb.ListOrdersRequest) (*httppb.ListOrdersResponse, error) {
+	return c.client.ListOrders(ctx, req)
+}
+
+// CancelOrder returns a rejected cancel as a response with accepted unset,
+// like the 409 Conflict of the HTTP API.
+func (c *GRPCOrderClient) CancelOrder(ctx context.Context, orderID, customerID string) (*httppb.CancelOrderResponse, error) {
+	resp, err := c.client.CancelOrder(ctx, &httppb.CancelOrderRequest{OrderId: orderID, CustomerId: customerID})
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrOrderNotFound
+	}
+	return resp, err
+}
+
+func (c *GRPCOrderClient) RefundOrder(ctx context.Context, orderID string, req *httppb.RefundOrderRequest) (*httppb.RefundOrderResponse, error) {
+	req = proto.Clone(req).(*httppb.RefundOrderRequest)
+	req.OrderId = orderID
+
+	resp, err := c.client.RefundOrder(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		return resp, nil
+	case codes.InvalidArgument:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRefund, status.Convert(err).Message())
+	case codes.FailedPrecondition:
+		return nil, fmt.Errorf("%w: %s", ErrRefundRejected, status.Convert(err).Message())
+	case codes.NotFound:
+		return nil, ErrOrderNotFound
+	default:
+		return nil, err
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 
 	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/pkg/proto/rpc"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -64,3 +66,24 @@ func (c *HTTPPaymentClient) PaymentFail(ctx context.Context, req *httppb.Payment
 
 	return nil
 }
+
+// GRPCPaymentClient talks to the PaymentService gRPC API.
+type GRPCPaymentClient struct {
+	client rpc.PaymentServiceClient
+}
+
+func NewGRPCPaymentClient(conn *grpc.ClientConn) *GRPCPaymentClient {
+	return &GRPCPaymentClient{
+		client: rpc.NewPaymentServiceClient(conn),
+	}
+}
+
+func (c *GRPCPaymentClient) PaymentSuccess(ctx context.Context, req *httppb.PaymentSuccessRequest) error {
+	_, err := c.client.PaymentSuccess(ctx, req)
+	return err
+}
+
+func (c *GRPCPaymentClient) PaymentFail(ctx context.Context, req *httppb.PaymentFailRequest) error {
+	_, err := c.client.PaymentFail(ctx, req)
+	return err
+}
@@ -2,42 +2,35 @@ package consumer
 
 import (
 	"context"
-	"io"
 	"log/slog"
 
-	"errors"
-
+	kafkaadapter "github.com/axmz/go-saga-microservices/lib/adapter/kafka"
 	"github.com/axmz/go-saga-microservices/services/storefront/internal/handler"
 	"github.com/segmentio/kafka-go"
 )
 
 type Consumer struct {
-	Reader  *kafka.Reader
+	Broker  *kafkaadapter.Broker
 	Handler *handler.Handler
 }
 
-func New(r *kafka.Reader, h *handler.Handler) *Consumer {
-	return &Consumer{Reader: r, Handler: h}
+func New(b *kafkaadapter.Broker, h *handler.Handler) *Consumer {
+	return &Consumer{Broker: b, Handler: h}
 }
 
 func (c *Consumer) Start(ctx context.Context) error {
 	slog.Info("Consumer started")
-	for {
-		m, err := c.Reader.ReadMessage(ctx)
-		if err != nil {
-			if errors.Is(err, kafka.ErrGroupClosed) ||
-				errors.Is(err, io.EOF) ||
-				ctx.Err() != nil {
-				return err
-			}
-			slog.Warn("Kafka read error:", "err", err)
-			continue
-		}
-		switch m.Topic {
-		case "payment.events":
-			c.Handler.PaymentEvents(ctx, m)
-		default:
-			slog.Warn("Unhandled event")
-		}
+	return c.Broker.ListenAndHandle(ctx, c.handle)
+}
+
+func (c *Consumer) handle(ctx context.Context, m kafka.Message) error {
+	switch m.Topic {
+	case "payment.events":
+		return c.Handler.PaymentEvents(ctx, m)
+	case "shipping.events":
+		return c.Handler.ShippingEvents(ctx, m)
+	default:
+		slog.Warn("Unhandled event")
+		return nil
 	}
 }
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// customerCookie holds the id of the anonymous customer using the browser.
+const customerCookie = "customer_id"
+
+// customerID returns the customer id of the browser, issuing one in a cookie on
+// its first visit. The storefront has no accounts; the cookie is what ties the
+// orders of one customer together.
+func customerID(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(customerCookie); err == nil && c.Value != "" && len(c.Value) <= 64 {
+		return c.Value
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	id := hex.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     customerCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
@@ -8,8 +8,10 @@ import (
 	"net/http"
 
 	httputils "github.com/axmz/go-saga-microservices/lib/adapter/http"
+	"github.com/axmz/go-saga-microservices/lib/adapter/kafka/codec"
 	"github.com/axmz/go-saga-microservices/pkg/proto/events"
 	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"github.com/axmz/go-saga-microservices/services/storefront/internal/client"
 	"github.com/axmz/go-saga-microservices/services/storefront/internal/renderer"
 	"github.com/axmz/go-saga-microservices/services/storefront/internal/service"
 	"github.com/axmz/go-saga-microservices/services/storefront/internal/ws"
@@ -23,6 +25,16 @@ const (
 	OrderIDPathParam = "orderId"
 )
 
+// orderStatuses are offered as filters on the admin orders page.
+var orderStatuses = []string{"Pending", "AwaitingPayment", "Paid", "Compensating", "Failed", "Cancelling", "Cancelled", "Refunding", "Refunded", "Shipped", "Delivered"}
+
+// productStatuses and productSorts are offered as filters and orders on the
+// home page; the first sort is the default.
+var (
+	productStatuses = []string{"available", "reserved", "sold"}
+	productSorts    = []string{"name", "price", "-price", "-created_at"}
+)
+
 type Handler struct {
 	Service   *service.Service
 	Renderer  *renderer.TemplateRenderer
@@ -46,15 +58,38 @@ func (h *Handler) HomePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	products, err := h.Service.GetProducts(r.Context())
+	req, err := httputils.ParseGetProductsRequest(r)
+	if err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+	resp, err := h.Service.GetProducts(r.Context(), req)
+	if errors.Is(err, client.ErrInvalidProductQuery) {
+		slog.Warn("GetProducts rejected", "err", err)
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
 	if err != nil {
 		slog.Error("GetProducts failed", "err", err)
 		httputils.ErrorInternal(w, err)
 		return
 	}
 
+	// the next page keeps the filters and only swaps the cursor
+	var nextPage string
+	if resp.NextCursor != "" {
+		q := r.URL.Query()
+		q.Set("cursor", resp.NextCursor)
+		nextPage = "/?" + q.Encode()
+	}
+
+	customerID(w, r)
 	if err = h.Renderer.Render(w, "home.html", map[string]any{
-		"Products": products,
+		"Products": resp.Products,
+		"Filter":   req,
+		"Statuses": productStatuses,
+		"Sorts":    productSorts,
+		"NextPage": nextPage,
 		"Title":    "Saga Microservices Storefront",
 	}); err != nil {
 		slog.Error("Render home.html failed", "err", err)
@@ -104,8 +139,15 @@ func (h *Handler) OrderPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.Renderer.Render(w, "order.html", map[string]*httppb.Order{
-		"Order": order,
+	// the page still works without the timeline
+	history, err := h.Service.GetOrderHistory(r.Context(), orderID)
+	if err != nil {
+		slog.Warn("GetOrderHistory failed", "orderId", orderID, "err", err)
+	}
+
+	if err = h.Renderer.Render(w, "order.html", map[string]any{
+		"Order":   order,
+		"History": history,
 	}); err != nil {
 		slog.Error("Render order.html failed", "orderId", orderID, "err", err)
 		httputils.ErrorInternal(w, err)
@@ -139,17 +181,75 @@ func (h *Handler) ConfirmationPage(w http.ResponseWriter, r *http.Request) {
 	slog.Info("ConfirmationPage served", "orderId", orderID, "status", http.StatusOK)
 }
 
+func (h *Handler) AdminOrdersPage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &httppb.ListOrdersRequest{
+		Status:      q.Get("status"),
+		CreatedFrom: q.Get("created_from"),
+		CreatedTo:   q.Get("created_to"),
+		Sku:         q.Get("sku"),
+		CustomerId:  q.Get("customer_id"),
+		Cursor:      q.Get("cursor"),
+	}
+
+	resp, err := h.Service.ListOrders(r.Context(), req)
+	if err != nil {
+		slog.Error("ListOrders failed", "err", err)
+		httputils.ErrorInternal(w, err)
+		return
+	}
+
+	// the next page keeps the filters and only swaps the cursor
+	var nextPage string
+	if resp.NextCursor != "" {
+		q.Set("cursor", resp.NextCursor)
+		nextPage = "/admin/orders?" + q.Encode()
+	}
+
+	if err = h.Renderer.Render(w, "admin_orders.html", map[string]any{
+		"Admin":    true,
+		"Title":    "Orders",
+		"Orders":   resp.Orders,
+		"Filter":   req,
+		"Statuses": orderStatuses,
+		"NextPage": nextPage,
+	}); err != nil {
+		slog.Error("Render admin_orders.html failed", "err", err)
+		httputils.ErrorInternal(w, err)
+		return
+	}
+	slog.Info("AdminOrdersPage served", "count", len(resp.Orders), "status", http.StatusOK)
+}
+
 // API
 func (h *Handler) APIGetProducts(w http.ResponseWriter, r *http.Request) {
-	products, err := h.Service.GetProducts(r.Context())
+	req, err := httputils.ParseGetProductsRequest(r)
+	if err != nil {
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+	resp, err := h.Service.GetProducts(r.Context(), req)
+	if errors.Is(err, client.ErrInvalidProductQuery) {
+		slog.Warn("APIGetProducts rejected", "err", err)
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
 	if err != nil {
 		slog.Error("APIGetProducts GetProducts failed", "err", err)
 		httputils.ErrorInternal(w, err)
 		return
 	}
 
-	slog.Info("APIGetProducts success", "count", len(products))
-	h.respondWithGetProductsResponse(w, products)
+	// the inventory ETag names the catalog version, so it holds here too
+	w.Header().Set("ETag", resp.Etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if match := r.Header.Get("If-None-Match"); match != "" && httputils.ETagMatches(match, resp.Etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	slog.Info("APIGetProducts success", "count", len(resp.Products))
+	h.respondWithGetProductsResponse(w, resp)
 }
 
 func (h *Handler) APICreateOrder(w http.ResponseWriter, r *http.Request) {
@@ -159,16 +259,116 @@ func (h *Handler) APICreateOrder(w http.ResponseWriter, r *http.Request) {
 		httputils.ErrorBadRequest(w, err)
 		return
 	}
+	// the customer is whoever the cookie says, never what the request body says
+	req.CustomerId = customerID(w, r)
+
+	order, err := h.Service.CreateOrder(r.Context(), req, r.Header.Get("Idempotency-Key"))
+	if err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidOrder):
+			slog.Warn("CreateOrder rejected", "err", err)
+			httputils.ErrorBadRequest(w, err)
+		case errors.Is(err, client.ErrIdempotencyKeyReused):
+			slog.Warn("CreateOrder idempotency key reused", "err", err)
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			slog.Error("CreateOrder failed", "err", err)
+			httputils.ErrorInternal(w, err)
+		}
+		return
+	}
+
+	slog.Info("APICreateOrder success", "orderId", order.Id, "status", order.Status)
+	if order.Status == "Pending" {
+		w.Header().Set("Location", "/order/"+order.Id)
+		h.respondWithCreateOrderSuccess(w, order, http.StatusAccepted)
+		return
+	}
+	h.respondWithCreateOrderSuccess(w, order, http.StatusCreated)
+}
+
+func (h *Handler) APIGetOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue(OrderIDPathParam)
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
+		return
+	}
+
+	order, err := h.Service.GetOrder(r.Context(), orderID)
+	if err != nil {
+		slog.Error("APIGetOrder GetOrder failed", "orderId", orderID, "err", err)
+		httputils.ErrorInternal(w, err)
+		return
+	}
+
+	h.respondWithGetOrderResponse(w, order)
+}
 
-	order, err := h.Service.CreateOrder(r.Context(), req)
+func (h *Handler) APICancelOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue(OrderIDPathParam)
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
+		return
+	}
+
+	resp, err := h.Service.CancelOrder(r.Context(), orderID, customerID(w, r))
 	if err != nil {
-		slog.Error("CreateOrder failed", "err", err)
+		if errors.Is(err, client.ErrOrderNotFound) {
+			slog.Warn("APICancelOrder order not found", "orderId", orderID)
+			httputils.ErrorNotFound(w, err)
+			return
+		}
+		slog.Error("APICancelOrder CancelOrder failed", "orderId", orderID, "err", err)
 		httputils.ErrorInternal(w, err)
 		return
 	}
 
-	slog.Info("APICreateOrder success", "orderId", order.Id)
-	h.respondWithCreateOrderSuccess(w, order)
+	if !resp.Accepted {
+		slog.Info("APICancelOrder rejected", "orderId", orderID, "status", resp.Status)
+		h.respondWithCancelOrder(w, resp, http.StatusConflict)
+		return
+	}
+
+	slog.Info("APICancelOrder success", "orderId", orderID)
+	h.respondWithCancelOrder(w, resp, http.StatusOK)
+}
+
+func (h *Handler) APIRefundOrder(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue(OrderIDPathParam)
+	if orderID == "" {
+		httputils.ErrorBadRequest(w, errors.New("missing orderId"))
+		return
+	}
+
+	req, err := h.processRefundOrderRequest(r)
+	if err != nil {
+		slog.Warn("APIRefundOrder bad request", "err", err)
+		httputils.ErrorBadRequest(w, err)
+		return
+	}
+
+	req.CustomerId = customerID(w, r)
+	resp, err := h.Service.RefundOrder(r.Context(), orderID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, client.ErrOrderNotFound):
+			slog.Warn("APIRefundOrder order not found", "orderId", orderID)
+			httputils.ErrorNotFound(w, err)
+		case errors.Is(err, client.ErrInvalidRefund):
+			slog.Warn("APIRefundOrder invalid refund", "orderId", orderID, "err", err)
+			httputils.ErrorBadRequest(w, err)
+		case errors.Is(err, client.ErrRefundRejected):
+			slog.Info("APIRefundOrder rejected", "orderId", orderID, "err", err)
+			httputils.ErrorConflict(w, err)
+		default:
+			slog.Error("APIRefundOrder RefundOrder failed", "orderId", orderID, "err", err)
+			httputils.ErrorInternal(w, err)
+		}
+		return
+	}
+
+	slog.Info("APIRefundOrder success", "orderId", orderID, "refundId", resp.GetRefund().GetId())
+	httputils.RespondJSON(w, resp, http.StatusAccepted)
 }
 
 func (h *Handler) APIPaymentSuccess(w http.ResponseWriter, r *http.Request) {
@@ -179,7 +379,7 @@ func (h *Handler) APIPaymentSuccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.Service.PaymentSuccess(r.Context(), req.OrderId); err != nil {
+	if err := h.Service.PaymentSuccess(r.Context(), req); err != nil {
 		slog.Error("PaymentSuccess failed", "orderId", req.OrderId, "err", err)
 		httputils.ErrorInternal(w, err)
 		return
@@ -225,7 +425,7 @@ func (h *Handler) WSOrderStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conn, err := ws.Upgrader.Upgrade(w, r, nil)
+	conn, err := h.WSManager.Upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Warn("WebSocket upgrade error:", "err", err)
 		return
@@ -248,12 +448,11 @@ func (h *Handler) WSOrderStatus(w http.ResponseWriter, r *http.Request) {
 }
 
 // EVENTS
-func (h *Handler) PaymentEvents(ctx context.Context, m kafka.Message) {
+func (h *Handler) PaymentEvents(ctx context.Context, m kafka.Message) error {
 	slog.Info("PaymentEvents received", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
-	var envelope events.PaymentEventEnvelope
-	if err := proto.Unmarshal(m.Value, &envelope); err != nil {
-		slog.Warn("Failed to unmarshal PaymentEventEnvelope:", "err", err)
-		return
+	envelope, err := codec.Decode[*events.PaymentEventEnvelope](m)
+	if err != nil {
+		return err
 	}
 
 	switch evt := envelope.Event.(type) {
@@ -265,10 +464,44 @@ func (h *Handler) PaymentEvents(ctx context.Context, m kafka.Message) {
 		orderID := evt.PaymentFailed.Id
 		slog.Info("Payment event: failed", "orderId", orderID)
 		h.WSManager.Broadcast(orderID, "Failed")
+	case *events.PaymentEventEnvelope_PaymentRefunded:
+		// the order service moves the order on, nothing to show before that
+		slog.Info("Payment event: refunded", "orderId", evt.PaymentRefunded.Id)
+	case *events.PaymentEventEnvelope_RefundSucceeded:
+		// the order service decides whether the order is refunded in full
+		slog.Info("Payment event: refund succeeded", "orderId", evt.RefundSucceeded.Id, "refundId", evt.RefundSucceeded.RefundId)
+	case *events.PaymentEventEnvelope_RefundFailed:
+		slog.Info("Payment event: refund failed", "orderId", evt.RefundFailed.Id, "refundId", evt.RefundFailed.RefundId, "reason", evt.RefundFailed.Reason)
 
 	default:
 		slog.Warn("Unknown or missing event type in envelope")
 	}
+	return nil
+}
+
+func (h *Handler) ShippingEvents(ctx context.Context, m kafka.Message) error {
+	slog.Info("ShippingEvents received", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
+	envelope, err := codec.Decode[*events.ShippingEventEnvelope](m)
+	if err != nil {
+		return err
+	}
+
+	switch evt := envelope.Event.(type) {
+	case *events.ShippingEventEnvelope_ShipmentCreated:
+		// the order stays Paid until the carrier picks the parcel up
+		slog.Info("Shipping event: shipment created", "orderId", evt.ShipmentCreated.Id, "trackingNumber", evt.ShipmentCreated.TrackingNumber)
+	case *events.ShippingEventEnvelope_ShipmentDispatched:
+		orderID := evt.ShipmentDispatched.Id
+		slog.Info("Shipping event: shipment dispatched", "orderId", orderID)
+		h.WSManager.Broadcast(orderID, "Shipped")
+	case *events.ShippingEventEnvelope_ShipmentDelivered:
+		orderID := evt.ShipmentDelivered.Id
+		slog.Info("Shipping event: shipment delivered", "orderId", orderID)
+		h.WSManager.Broadcast(orderID, "Delivered")
+	default:
+		slog.Warn("Unknown or missing event type in envelope")
+	}
+	return nil
 }
 
 // REQ PROCESSING
@@ -296,12 +529,21 @@ func (h *Handler) processCreateOrderRequest(r *http.Request) (*httppb.CreateOrde
 	return req, nil
 }
 
+func (h *Handler) processRefundOrderRequest(r *http.Request) (*httppb.RefundOrderRequest, error) {
+	req := new(httppb.RefundOrderRequest)
+	if err := h.parseProtoJSONBody(r, req); err != nil {
+		return nil, err
+	}
+	slog.Debug("Parsed RefundOrderRequest", "items", len(req.GetItems()))
+	return req, nil
+}
+
 func (h *Handler) processPaymentSuccessRequest(r *http.Request) (*httppb.PaymentSuccessRequest, error) {
 	req := new(httppb.PaymentSuccessRequest)
 	if err := h.parseProtoJSONBody(r, req); err != nil {
 		return nil, err
 	}
-	slog.Debug("Parsed PaymentSuccessRequest", "orderId", req.OrderId)
+	slog.Debug("Parsed PaymentSuccessRequest", "orderId", req.OrderId, "amountMinor", req.AmountMinor, "currency", req.Currency)
 	return req, nil
 }
 
@@ -325,12 +567,20 @@ func (h *Handler) respondWithPaymentSuccess(w http.ResponseWriter) {
 	httputils.RespondJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) respondWithGetProductsResponse(w http.ResponseWriter, products []*httppb.Product) {
-	response := &httppb.GetProductsResponse{Products: products}
+func (h *Handler) respondWithGetProductsResponse(w http.ResponseWriter, response *httppb.GetProductsResponse) {
 	httputils.RespondJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) respondWithCreateOrderSuccess(w http.ResponseWriter, order *httppb.Order) {
+func (h *Handler) respondWithCreateOrderSuccess(w http.ResponseWriter, order *httppb.Order, statusCode int) {
 	response := &httppb.CreateOrderResponse{Order: order}
-	httputils.RespondJSON(w, response, http.StatusCreated)
+	httputils.RespondJSON(w, response, statusCode)
+}
+
+func (h *Handler) respondWithGetOrderResponse(w http.ResponseWriter, order *httppb.Order) {
+	response := &httppb.GetOrderResponse{Order: order}
+	httputils.RespondJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) respondWithCancelOrder(w http.ResponseWriter, response *httppb.CancelOrderResponse, statusCode int) {
+	httputils.RespondJSON(w, response, statusCode)
 }
@@ -3,6 +3,7 @@ package renderer
 import (
 	"embed"
 	"errors"
+	"fmt"
 	"html/template"
 	"io/fs"
 	"net/http"
@@ -14,6 +15,20 @@ var templatesFS embed.FS
 
 type TemplateCache map[string]*template.Template
 
+var funcs = template.FuncMap{
+	"money": money,
+}
+
+// money formats an amount in minor units, cents for the two-decimal currencies
+// the shop sells in.
+func money(minor int64) string {
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/100, minor%100)
+}
+
 type TemplateRenderer struct {
 	templates TemplateCache
 }
@@ -34,7 +49,7 @@ func NewTemplateCache() (TemplateCache, error) {
 	for _, page := range pages {
 		files := append(layouts, page)
 		name := filepath.Base(page)
-		tmpl, err := template.ParseFS(templatesFS, files...)
+		tmpl, err := template.New(name).Funcs(funcs).ParseFS(templatesFS, files...)
 		if err != nil {
 			return nil, err
 		}
@@ -21,6 +21,9 @@ var (
 	routePaymentPage      = fmt.Sprintf("GET /payment/{%s}", OrderIDPathParam)
 	routeConfirmationPage = fmt.Sprintf("GET /confirmation/{%s}", OrderIDPathParam)
 	routeWSOrder          = fmt.Sprintf("GET /orders/ws/{%s}", OrderIDPathParam)
+	routeAPIOrder         = fmt.Sprintf("GET /api/orders/{%s}", OrderIDPathParam)
+	routeAPICancelOrder   = fmt.Sprintf("POST /api/orders/{%s}/cancel", OrderIDPathParam)
+	routeAPIRefundOrder   = fmt.Sprintf("POST /api/orders/{%s}/refund", OrderIDPathParam)
 )
 
 func New(handlers *handler.Handler, svc *service.Service, renderer *renderer.TemplateRenderer) *http.ServeMux {
@@ -37,8 +40,25 @@ func New(handlers *handler.Handler, svc *service.Service, renderer *renderer.Tem
 
 	mux.HandleFunc("GET /api/products", handlers.APIGetProducts)
 	mux.HandleFunc("POST /api/orders", handlers.APICreateOrder)
+	mux.HandleFunc(routeAPIOrder, handlers.APIGetOrder)
+	mux.HandleFunc(routeAPICancelOrder, handlers.APICancelOrder)
+	mux.HandleFunc(routeAPIRefundOrder, handlers.APIRefundOrder)
 	mux.HandleFunc("POST /api/payment-success", handlers.APIPaymentSuccess)
 	mux.HandleFunc("POST /api/payment-fail", handlers.APIPaymentFail)
+
+	return mux
+}
+
+// Admin routes the support pages and the product reset. It is mounted on the
+// internal admin listener, not on the public storefront: the orders it lists
+// carry the customer ids the storefront identifies customers by.
+func Admin(handlers *handler.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.Handle(static, http.StripPrefix(static, http.FileServer(http.Dir("static"))))
+	mux.HandleFunc("GET /admin/orders", handlers.AdminOrdersPage)
+	mux.HandleFunc(routeOrderPage, handlers.OrderPage)
+	mux.HandleFunc(routeAPIOrder, handlers.APIGetOrder)
 	mux.HandleFunc("POST /api/admin/reset-products", handlers.APIResetProducts)
 
 	return mux
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	httppb "github.com/axmz/go-saga-microservices/pkg/proto/http"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxCatalogPages bounds how many product pages are kept; when it is
+// reached the pages are dropped and fetched again.
+const maxCatalogPages = 128
+
+// catalogCache keeps the last page of products of each query together with
+// its ETag, so a page can be revalidated instead of fetched again.
+type catalogCache struct {
+	mu    sync.Mutex
+	pages map[string]*httppb.GetProductsResponse
+}
+
+func newCatalogCache() *catalogCache {
+	return &catalogCache{pages: make(map[string]*httppb.GetProductsResponse)}
+}
+
+func (c *catalogCache) get(key string) *httppb.GetProductsResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pages[key]
+}
+
+func (c *catalogCache) put(key string, page *httppb.GetProductsResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pages) >= maxCatalogPages {
+		clear(c.pages)
+	}
+	c.pages[key] = page
+}
+
+// GetProducts lists a page of products. A page seen before is revalidated
+// with the inventory service by its ETag and only fetched again when the
+// catalog changed since.
+func (s *Service) GetProducts(ctx context.Context, req *httppb.GetProductsRequest) (*httppb.GetProductsResponse, error) {
+	query := proto.CloneOf(req)
+	query.IfNoneMatch = ""
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	key := string(b)
+
+	cached := s.catalog.get(key)
+	if cached != nil {
+		query.IfNoneMatch = cached.Etag
+	}
+	resp, err := s.inventoryClient.GetProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if resp.NotModified && cached != nil {
+		return cached, nil
+	}
+	s.catalog.put(key, resp)
+	return resp, nil
+}
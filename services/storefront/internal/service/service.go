@@ -13,6 +13,7 @@ type Service struct {
 	orderClient     client.OrderClient
 	paymentClient   client.PaymentClient
 	inventoryClient client.InventoryClient
+	catalog         *catalogCache
 }
 
 func New(cfg *config.Config, orderClient client.OrderClient, paymentClient client.PaymentClient, inventoryClient client.InventoryClient) *Service {
@@ -21,6 +22,7 @@ func New(cfg *config.Config, orderClient client.OrderClient, paymentClient clien
 		orderClient:     orderClient,
 		paymentClient:   paymentClient,
 		inventoryClient: inventoryClient,
+		catalog:         newCatalogCache(),
 	}
 }
 
@@ -32,20 +34,25 @@ func (s *Service) GetOrder(ctx context.Context, orderID string) (*httppb.Order,
 	return resp.Order, nil
 }
 
-func (s *Service) GetProducts(ctx context.Context) ([]*httppb.Product, error) {
-	resp, err := s.inventoryClient.GetProducts(ctx)
+func (s *Service) GetOrderHistory(ctx context.Context, orderID string) ([]*httppb.OrderStatusChange, error) {
+	resp, err := s.orderClient.GetOrderHistory(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Products, nil
+	return resp.Changes, nil
+}
+
+func (s *Service) ListOrders(ctx context.Context, req *httppb.ListOrdersRequest) (*httppb.ListOrdersResponse, error) {
+	return s.orderClient.ListOrders(ctx, req)
 }
 
 func (s *Service) ResetInventory(ctx context.Context) error {
 	return s.inventoryClient.ResetAll(ctx)
 }
 
-func (s *Service) CreateOrder(ctx context.Context, orderReq *httppb.CreateOrderRequest) (*httppb.Order, error) {
-	resp, err := s.orderClient.CreateOrder(ctx, orderReq)
+func (s *Service) CreateOrder(ctx context.Context, orderReq *httppb.CreateOrderRequest, idempotencyKey string) (*httppb.Order, error) {
+	// the order service prices the items itself
+	resp, err := s.orderClient.CreateOrder(ctx, orderReq, idempotencyKey)
 	if err != nil {
 		return nil, err
 	}
@@ -53,9 +60,18 @@ func (s *Service) CreateOrder(ctx context.Context, orderReq *httppb.CreateOrderR
 	return resp.Order, nil
 }
 
-func (s *Service) PaymentSuccess(ctx context.Context, orderID string) error {
-	protoReq := &httppb.PaymentSuccessRequest{OrderId: orderID}
-	return s.paymentClient.PaymentSuccess(ctx, protoReq)
+func (s *Service) CancelOrder(ctx context.Context, orderID, customerID string) (*httppb.CancelOrderResponse, error) {
+	return s.orderClient.CancelOrder(ctx, orderID, customerID)
+}
+
+func (s *Service) RefundOrder(ctx context.Context, orderID string, req *httppb.RefundOrderRequest) (*httppb.RefundOrderResponse, error) {
+	return s.orderClient.RefundOrder(ctx, orderID, req)
+}
+
+// PaymentSuccess passes on what the customer was charged; payment checks it
+// against the order total.
+func (s *Service) PaymentSuccess(ctx context.Context, req *httppb.PaymentSuccessRequest) error {
+	return s.paymentClient.PaymentSuccess(ctx, req)
 }
 
 func (s *Service) PaymentFail(ctx context.Context, orderID string) error {
@@ -2,14 +2,29 @@ package ws
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/gorilla/websocket"
 )
 
-var Upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Change this to allow only your frontend origin
-	},
+// NewUpgrader returns an upgrader that accepts connections from pages of the
+// storefront only: the origin must be the host the request was sent to or
+// the configured storefront host. Requests without an Origin header do not
+// come from a browser and are let through.
+func NewUpgrader(storefrontHost string) websocket.Upgrader {
+	return websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			u, err := url.Parse(origin)
+			if err != nil {
+				return false
+			}
+			return u.Host == r.Host || u.Hostname() == storefrontHost
+		},
+	}
 }
@@ -12,10 +12,14 @@ type WSManager struct {
 	mu              sync.RWMutex
 	clients         map[string]map[*websocket.Conn]bool
 	lastKnownStatus map[string]string
+	Upgrader        websocket.Upgrader
 }
 
-func NewWSManager() *WSManager {
+// NewWSManager creates a manager whose Upgrader accepts connections from
+// storefrontHost, see NewUpgrader.
+func NewWSManager(storefrontHost string) *WSManager {
 	return &WSManager{
+		Upgrader:        NewUpgrader(storefrontHost),
 		clients:         make(map[string]map[*websocket.Conn]bool),
 		lastKnownStatus: make(map[string]string),
 	}